@@ -0,0 +1,57 @@
+// Package appenders contains golog appenders which are sending logs
+// to destinations other than stdout.
+package appenders
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ivpusic/golog"
+)
+
+// Will return name of logger which made log.
+// Logger names are normalized by golog, so trailing spaces are removed.
+func loggerName(log golog.Log) string {
+	if log.Logger == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(log.Logger.Name)
+}
+
+// Will print error which happened while appending log.
+// If logger which made log is configured to panic, appender will panic.
+func reportError(log golog.Log, err error) {
+	fmt.Println(err.Error())
+	if log.Logger != nil && log.Logger.DoPanic {
+		panic(err)
+	}
+}
+
+// Will return log with its own copy of context.
+// Context is shared with logger which can change it later,
+// so appenders which keep logs after Append returns have to copy it.
+func detachLog(log golog.Log) golog.Log {
+	if len(log.Ctx) > 0 {
+		ctx := make(golog.Ctx, len(log.Ctx))
+		for k, v := range log.Ctx {
+			ctx[k] = v
+		}
+
+		log.Ctx = ctx
+	}
+
+	return log
+}
+
+// Will return keys of context in sorted order.
+func sortedKeys(ctx golog.Ctx) []string {
+	keys := make([]string, 0, len(ctx))
+	for k := range ctx {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,34 @@
+package appenders
+
+import (
+	"time"
+)
+
+// Exponential backoff used by appenders when reconnecting,
+// or when retrying failed requests.
+type backoff struct {
+	min     time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+// Will return next delay. Every next delay is two times bigger
+// than previous one, until max delay is reached.
+func (b *backoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.min
+	} else {
+		b.current *= 2
+	}
+
+	if b.current > b.max {
+		b.current = b.max
+	}
+
+	return b.current
+}
+
+// Will start from min delay again.
+func (b *backoff) reset() {
+	b.current = 0
+}
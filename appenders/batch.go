@@ -0,0 +1,350 @@
+package appenders
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+var (
+	errBatchDropped = errors.New("batch: too many pending batches, oldest batch is dropped")
+	errBatchClosed  = errors.New("batch: appender is closed")
+)
+
+// Interface for destinations which can write many logs at once.
+// HTTP, Loki, Elasticsearch, OTLP, Fluentd, SQL and Mongo appenders implement it,
+// and custom destinations can be plugged into logger using Batch function.
+type BatchAppender interface {
+	// method for writing batch of logs
+	// if error is returned, whole batch is considered as failed
+	AppendBatch(logs []golog.Log) error
+
+	// method will return appender ID
+	Id() string
+}
+
+// Representing appender which collects logs in batches,
+// and writes them to batch appender from background goroutine.
+type BatchingAppender struct {
+	appender BatchAppender
+	batcher  *batcher
+}
+
+// Id of wrapped batch appender.
+func (ba *BatchingAppender) Id() string {
+	return ba.appender.Id()
+}
+
+func (ba *BatchingAppender) Append(log golog.Log) {
+	if err := ba.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+// Error is returned only if log is not accepted, because appender is closed.
+// Older batches which are dropped because too many batches are waiting to be written
+// are reported to error handler. The same applies to all appenders which send logs in batches.
+func (ba *BatchingAppender) TryAppend(log golog.Log) error {
+	return ba.batcher.add(log)
+}
+
+// Will set function which is called when batch of logs cannot be written.
+// By default errors are printed.
+func (ba *BatchingAppender) OnError(fn func(err error, logs []golog.Log)) *BatchingAppender {
+	ba.batcher.setErrorHandler(fn)
+	return ba
+}
+
+func (ba *BatchingAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	ba.batcher.watchers.add(fn)
+}
+
+// Will write current batch, and wait until all pending batches are written.
+func (ba *BatchingAppender) Flush() error {
+	return ba.batcher.flush()
+}
+
+// Will write all collected logs and stop appender.
+// Wrapped appender is closed too, if it has Close method.
+func (ba *BatchingAppender) Close() error {
+	err := ba.batcher.close()
+
+	if c, ok := ba.appender.(closer); ok {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Function for creating appender which writes logs to batch appender in batches.
+// Supported configuration keys are:
+// batch_size - max number of logs in one batch (default 100)
+// batch_bytes - max estimated size of batch in bytes (default 1MB)
+// flush_interval - max time before collected logs are written, 0 to disable (default 1s)
+// max_pending - max number of batches waiting to be written, oldest one is dropped if there are more (default 10)
+func Batch(appender BatchAppender, cnf golog.Conf) *BatchingAppender {
+	return &BatchingAppender{
+		appender: appender,
+		batcher:  newBatcher(cnf, appender.AppendBatch),
+	}
+}
+
+// Batch of logs waiting to be sent.
+// If done channel is set, it is closed after batch is processed.
+type pendingBatch struct {
+	logs []golog.Log
+	done chan struct{}
+}
+
+// Collecting logs and sending them in batches from background goroutine.
+// Batch is sent when it reaches max number of logs, max size in bytes,
+// or when flush interval passes.
+type batcher struct {
+	maxCount int
+	maxBytes int
+	interval time.Duration
+	send     func([]golog.Log) error
+
+	mu      sync.Mutex
+	logs    []golog.Log
+	bytes   int
+	closed  bool
+	onError func(error, []golog.Log)
+
+	// composite appenders which are notified about logs which cannot be sent
+	watchers deliveryWatchers
+
+	queue chan pendingBatch
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newBatcher(cnf golog.Conf, send func([]golog.Log) error) *batcher {
+	pending := confInt(cnf, "max_pending", 10)
+	if pending <= 0 {
+		pending = 10
+	}
+
+	b := &batcher{
+		maxCount: confInt(cnf, "batch_size", 100),
+		maxBytes: confInt(cnf, "batch_bytes", 1024*1024),
+		interval: confDuration(cnf, "flush_interval", time.Second),
+		send:     send,
+		queue:    make(chan pendingBatch, pending),
+		done:     make(chan struct{}),
+	}
+
+	b.wg.Add(1)
+	go b.run()
+
+	if b.interval > 0 {
+		b.wg.Add(1)
+		go b.tick()
+	}
+
+	return b
+}
+
+// Will add log to current batch.
+// Error is returned only if batcher is closed. Dropped older batches are reported to error handler
+// and composite appenders, instead of failing log which is accepted.
+func (b *batcher) add(log golog.Log) error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return errBatchClosed
+	}
+
+	// logs are encoded later from background goroutine
+	b.logs = append(b.logs, detachLog(log))
+	b.bytes += estimateSize(log)
+
+	var dropped []pendingBatch
+	if (b.maxCount > 0 && len(b.logs) >= b.maxCount) || (b.maxBytes > 0 && b.bytes >= b.maxBytes) {
+		dropped = b.cut(nil)
+	}
+
+	b.mu.Unlock()
+	b.dropped(dropped)
+	return nil
+}
+
+// Will move current batch to queue of batches waiting to be sent.
+// If queue is full, oldest batches are dropped and returned.
+// Caller has to hold lock.
+func (b *batcher) cut(done chan struct{}) []pendingBatch {
+	batch := pendingBatch{b.logs, done}
+	b.logs = nil
+	b.bytes = 0
+
+	var dropped []pendingBatch
+	for {
+		select {
+		case b.queue <- batch:
+			return dropped
+		default:
+		}
+
+		select {
+		case old := <-b.queue:
+			dropped = append(dropped, old)
+		default:
+		}
+	}
+}
+
+// Reporting dropped batches. It is called without holding lock,
+// because error handler can make new logs.
+func (b *batcher) dropped(batches []pendingBatch) error {
+	if len(batches) == 0 {
+		return nil
+	}
+
+	for _, batch := range batches {
+		b.reportError(errBatchDropped, batch.logs)
+		if batch.done != nil {
+			close(batch.done)
+		}
+	}
+
+	return errBatchDropped
+}
+
+// Will send current batch, and wait until all pending batches are sent.
+func (b *batcher) flush() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errBatchClosed
+	}
+
+	done := make(chan struct{})
+	dropped := b.cut(done)
+	b.mu.Unlock()
+
+	err := b.dropped(dropped)
+	<-done
+	return err
+}
+
+// Will send all collected logs, and stop background goroutines.
+func (b *batcher) close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+
+	dropped := b.cut(nil)
+	b.closed = true
+	b.mu.Unlock()
+
+	err := b.dropped(dropped)
+	close(b.done)
+	b.wg.Wait()
+	return err
+}
+
+func (b *batcher) run() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case batch := <-b.queue:
+			b.process(batch)
+		case <-b.done:
+			// send everything what is left
+			for {
+				select {
+				case batch := <-b.queue:
+					b.process(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *batcher) process(batch pendingBatch) {
+	if len(batch.logs) > 0 {
+		if err := b.send(batch.logs); err != nil {
+			b.reportError(err, batch.logs)
+		}
+	}
+
+	if batch.done != nil {
+		close(batch.done)
+	}
+}
+
+func (b *batcher) tick() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var dropped []pendingBatch
+
+			b.mu.Lock()
+			if len(b.logs) > 0 && !b.closed {
+				dropped = b.cut(nil)
+			}
+			b.mu.Unlock()
+
+			b.dropped(dropped)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+func (b *batcher) setErrorHandler(fn func(error, []golog.Log)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.onError = fn
+}
+
+// Reporting batch which cannot be sent to error handler.
+// If there is no error handler, error is printed.
+// Composite appenders are notified too, so they can write logs somewhere else.
+func (b *batcher) reportError(err error, logs []golog.Log) {
+	b.mu.Lock()
+	fn := b.onError
+	b.mu.Unlock()
+
+	if fn != nil {
+		fn(err, logs)
+	} else {
+		fmt.Println(err.Error())
+	}
+
+	b.watchers.notify(err, logs)
+}
+
+// Will estimate size of log in bytes, without encoding it.
+func estimateSize(log golog.Log) int {
+	size := 128 + len(log.Message) + len(loggerName(log))
+
+	for k, v := range log.Ctx {
+		size += len(k) + 8
+		if s, ok := v.(string); ok {
+			size += len(s)
+		} else {
+			size += 16
+		}
+	}
+
+	return size + 32*len(log.Data)
+}
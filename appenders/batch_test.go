@@ -0,0 +1,161 @@
+package appenders
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Batch appender which remembers received batches, and fails while err is set
+type fakeBatchAppender struct {
+	mu      sync.Mutex
+	err     error
+	batches [][]string
+	closed  bool
+}
+
+func (fa *fakeBatchAppender) Id() string {
+	return "fake/batch"
+}
+
+func (fa *fakeBatchAppender) AppendBatch(logs []golog.Log) error {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	if fa.err != nil {
+		return fa.err
+	}
+
+	batch := []string{}
+	for _, log := range logs {
+		batch = append(batch, log.Message)
+	}
+
+	fa.batches = append(fa.batches, batch)
+	return nil
+}
+
+func (fa *fakeBatchAppender) Close() error {
+	fa.closed = true
+	return nil
+}
+
+func (fa *fakeBatchAppender) received() [][]string {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	return append([][]string{}, fa.batches...)
+}
+
+func TestBatchId(t *testing.T) {
+	appender := Batch(&fakeBatchAppender{}, golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "fake/batch", appender.Id())
+}
+
+func TestBatchSize(t *testing.T) {
+	sink := &fakeBatchAppender{}
+	appender := Batch(sink, golog.Conf{
+		"batch_size":     "2",
+		"flush_interval": "1h",
+	})
+
+	for _, msg := range []string{"a", "b", "c"} {
+		appender.Append(golog.Log{Message: msg})
+	}
+
+	assert.Nil(t, appender.Flush())
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, sink.received())
+
+	assert.Nil(t, appender.Close())
+	assert.True(t, sink.closed)
+	assert.Equal(t, errBatchClosed, appender.TryAppend(golog.Log{Message: "d"}))
+}
+
+func TestBatchBytes(t *testing.T) {
+	sink := &fakeBatchAppender{}
+	appender := Batch(sink, golog.Conf{
+		"batch_bytes":    "1000",
+		"flush_interval": "1h",
+	})
+
+	appender.Append(golog.Log{Message: "small"})
+	appender.Append(golog.Log{Message: strings.Repeat("a", 1000)})
+	appender.Append(golog.Log{Message: "next"})
+	assert.Nil(t, appender.Close())
+
+	batches := sink.received()
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Equal(t, []string{"next"}, batches[1])
+}
+
+func TestBatchInterval(t *testing.T) {
+	sink := &fakeBatchAppender{}
+	appender := Batch(sink, golog.Conf{"flush_interval": "20ms"})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "a"})
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, [][]string{{"a"}}, sink.received())
+}
+
+func TestBatchError(t *testing.T) {
+	sink := &fakeBatchAppender{err: errors.New("sink failed")}
+	appender := Batch(sink, golog.Conf{"flush_interval": "1h"})
+
+	var failed []golog.Log
+	appender.OnError(func(err error, logs []golog.Log) {
+		assert.Equal(t, "sink failed", err.Error())
+		failed = append(failed, logs...)
+	})
+
+	appender.Append(golog.Log{Message: "a"})
+	appender.Append(golog.Log{Message: "b"})
+	assert.Nil(t, appender.Close())
+	assert.Len(t, failed, 2)
+}
+
+func TestBatchCopiesContext(t *testing.T) {
+	var mu sync.Mutex
+	var values []interface{}
+	b := newBatcher(golog.Conf{"flush_interval": "1h"}, func(logs []golog.Log) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, log := range logs {
+			values = append(values, log.Ctx["key"])
+		}
+
+		return nil
+	})
+
+	ctx := golog.Ctx{"key": "value"}
+	assert.Nil(t, b.add(golog.Log{Message: "first", Ctx: ctx}))
+	ctx["key"] = "changed"
+
+	assert.Nil(t, b.close())
+	assert.Equal(t, []interface{}{"value"}, values)
+}
+
+func TestBatchLogger(t *testing.T) {
+	sink := &fakeBatchAppender{}
+	appender := Batch(sink, golog.Conf{"flush_interval": "1h"})
+
+	logger := golog.GetLogger("github.com/ivpusic/golog/appenders/batch_test")
+	logger.Enable(appender)
+	logger.Info("first")
+	logger.Info("second")
+
+	assert.Nil(t, appender.Flush())
+	assert.Equal(t, [][]string{{"first", "second"}}, sink.received())
+
+	logger.Disable("fake/batch")
+	appender.Close()
+}
@@ -0,0 +1,493 @@
+package appenders
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Appender which can report whether log is accepted.
+// Composite appenders use it to find out that appender failed.
+// Appenders which don't implement it are considered to always succeed.
+type TryAppender interface {
+	golog.Appender
+	TryAppend(log golog.Log) error
+}
+
+type flusher interface {
+	Flush() error
+}
+
+type closer interface {
+	Close() error
+}
+
+// Appender which delivers logs after accepting them, for example from background goroutine.
+// Composite appenders use it to find out that appender failed after log was accepted.
+type deliveryNotifier interface {
+	onDeliveryError(fn func(err error, logs []golog.Log))
+}
+
+// Appender which knows that its destination is unreachable, before log is written to it.
+type deliveryChecker interface {
+	deliveryError() error
+}
+
+// Functions which are called when logs accepted by appender cannot be delivered.
+// Logs are nil if appender keeps them, and delivers them after destination recovers.
+type deliveryWatchers struct {
+	mu  sync.Mutex
+	fns []func(error, []golog.Log)
+}
+
+func (dw *deliveryWatchers) add(fn func(error, []golog.Log)) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	dw.fns = append(dw.fns, fn)
+}
+
+func (dw *deliveryWatchers) notify(err error, logs []golog.Log) {
+	dw.mu.Lock()
+	fns := dw.fns
+	dw.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(err, logs)
+	}
+}
+
+func tryAppend(appender golog.Appender, log golog.Log) error {
+	if ta, ok := appender.(TryAppender); ok {
+		return ta.TryAppend(log)
+	}
+
+	appender.Append(log)
+	return nil
+}
+
+// Will register function which is called when appender cannot deliver accepted logs.
+func watchDelivery(appender golog.Appender, fn func(error, []golog.Log)) {
+	if dn, ok := appender.(deliveryNotifier); ok {
+		dn.onDeliveryError(fn)
+	}
+}
+
+// Checking whether destination of appender is known to be unreachable.
+func deliveryFailed(appender golog.Appender) bool {
+	if dc, ok := appender.(deliveryChecker); ok {
+		return dc.deliveryError() != nil
+	}
+
+	return false
+}
+
+// Will flush all appenders which support flushing, and return first error.
+func flushAll(appenders []golog.Appender) error {
+	var first error
+	for _, appender := range appenders {
+		if f, ok := appender.(flusher); ok {
+			if err := f.Flush(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+
+	return first
+}
+
+// Will close all appenders which support closing, and return first error.
+func closeAll(appenders []golog.Appender) error {
+	var first error
+	for _, appender := range appenders {
+		if c, ok := appender.(closer); ok {
+			if err := c.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+
+	return first
+}
+
+// Representing appender which writes logs to primary appender,
+// and switches to secondary appenders when it fails.
+// While secondary appender is used, primary appender is tried again
+// once per probe interval, and it is used again when it recovers.
+//
+// Appender fails when it rejects log, or when it cannot deliver logs which it accepted.
+// Appenders which send logs from background goroutine, like network, HTTP or Mongo appender,
+// report failed delivery to failover appender, and logs which they drop are written
+// to next appenders. Logs which they keep until destination recovers are not written again.
+// Network and Mongo appenders are also skipped while their destination is unreachable.
+type FailoverAppender struct {
+	appenders []golog.Appender
+	probe     time.Duration
+
+	mu      sync.Mutex
+	active  int
+	probeAt time.Time
+}
+
+// github.com/ivpusic/golog/appenders/failover
+func (fa *FailoverAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/failover"
+}
+
+func (fa *FailoverAppender) Append(log golog.Log) {
+	if err := fa.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will write log to active appender. If it fails, other appenders are tried in order,
+// and the first one which succeeds becomes active.
+// Error of last appender is returned if all of them fail.
+func (fa *FailoverAppender) TryAppend(log golog.Log) error {
+	// lock is not held while appenders are called,
+	// because they can report failed delivery from the same goroutine
+	fa.mu.Lock()
+	now := time.Now()
+	active := fa.active
+	start := active
+	if start > 0 && !now.Before(fa.probeAt) {
+		start = 0
+	}
+	fa.mu.Unlock()
+
+	order := make([]int, len(fa.appenders))
+	for n := range order {
+		order[n] = (start + n) % len(fa.appenders)
+	}
+
+	i, err := fa.appendTo(order, log)
+	if err != nil {
+		return err
+	}
+
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	// appender which failed meanwhile has already switched to next one
+	if fa.active != active {
+		return nil
+	}
+
+	if i > 0 && (i != active || start != active) {
+		fa.probeAt = now.Add(fa.probe)
+	}
+
+	fa.active = i
+	return nil
+}
+
+// Will write log to first appender in order which accepts it, and return its index.
+// Appenders whose destination is unreachable are tried after all other appenders.
+func (fa *FailoverAppender) appendTo(order []int, log golog.Log) (int, error) {
+	failed := make([]bool, len(fa.appenders))
+	for _, i := range order {
+		failed[i] = deliveryFailed(fa.appenders[i])
+	}
+
+	var err error
+	for _, tryFailed := range []bool{false, true} {
+		for _, i := range order {
+			if failed[i] != tryFailed {
+				continue
+			}
+
+			if err = tryAppend(fa.appenders[i], log); err == nil {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, err
+}
+
+// Called when appender at index i cannot deliver logs.
+// If it is active, next appender becomes active, and logs which it dropped
+// are written to next appenders.
+func (fa *FailoverAppender) undelivered(i int, logs []golog.Log) {
+	fa.mu.Lock()
+	if fa.active == i && i < len(fa.appenders)-1 {
+		fa.active = i + 1
+	}
+
+	if fa.active > i {
+		fa.probeAt = time.Now().Add(fa.probe)
+	}
+	fa.mu.Unlock()
+
+	var order []int
+	for n := i + 1; n < len(fa.appenders); n++ {
+		order = append(order, n)
+	}
+
+	if len(order) == 0 {
+		return
+	}
+
+	for _, log := range logs {
+		if _, err := fa.appendTo(order, log); err != nil {
+			reportError(log, err)
+		}
+	}
+}
+
+// Will return index of appender which is currently used, where 0 is primary appender.
+func (fa *FailoverAppender) Active() int {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	return fa.active
+}
+
+// Will set how often primary appender is tried while secondary appender is used.
+// Default is 30 seconds.
+func (fa *FailoverAppender) ProbeInterval(interval time.Duration) *FailoverAppender {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	fa.probe = interval
+	return fa
+}
+
+// Will flush all appenders which support flushing.
+func (fa *FailoverAppender) Flush() error {
+	return flushAll(fa.appenders)
+}
+
+// Will close all appenders which support closing.
+func (fa *FailoverAppender) Close() error {
+	return closeAll(fa.appenders)
+}
+
+// Function for creating failover appender.
+// Logs are written to primary appender, and secondary appenders are used in order when it fails.
+func Failover(primary golog.Appender, secondaries ...golog.Appender) *FailoverAppender {
+	fa := &FailoverAppender{
+		appenders: append([]golog.Appender{primary}, secondaries...),
+		probe:     30 * time.Second,
+	}
+
+	for i, appender := range fa.appenders {
+		i := i
+		watchDelivery(appender, func(_ error, logs []golog.Log) {
+			fa.undelivered(i, logs)
+		})
+	}
+
+	return fa
+}
+
+// Representing appender which writes every log to all of its appenders.
+// It can be used to enable or disable group of appenders using one id.
+type TeeAppender struct {
+	id        string
+	appenders []golog.Appender
+}
+
+// Id which is provided when appender is created,
+// or github.com/ivpusic/golog/appenders/tee if it is empty.
+func (ta *TeeAppender) Id() string {
+	if len(ta.id) == 0 {
+		return "github.com/ivpusic/golog/appenders/tee"
+	}
+
+	return ta.id
+}
+
+func (ta *TeeAppender) Append(log golog.Log) {
+	if err := ta.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will write log to all appenders, even if some of them fail.
+// Error of first failed appender is returned.
+func (ta *TeeAppender) TryAppend(log golog.Log) error {
+	var first error
+	for _, appender := range ta.appenders {
+		if err := tryAppend(appender, log); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Will flush all appenders which support flushing.
+func (ta *TeeAppender) Flush() error {
+	return flushAll(ta.appenders)
+}
+
+// Will close all appenders which support closing.
+func (ta *TeeAppender) Close() error {
+	return closeAll(ta.appenders)
+}
+
+// Function for creating tee appender with provided id.
+func Tee(id string, appenders ...golog.Appender) *TeeAppender {
+	return &TeeAppender{
+		id:        id,
+		appenders: appenders,
+	}
+}
+
+// Representing appender which balances logs across equivalent appenders,
+// for example network appenders which send logs to different instances of the same service.
+// Logs are written to appenders in turn. Appender which fails is skipped
+// for retry interval, and log is written to next appender.
+// Like in failover appender, appender fails also when it cannot deliver logs which it accepted,
+// and then logs which it dropped are written to other appenders.
+type RoundRobinAppender struct {
+	appenders []golog.Appender
+	retry     time.Duration
+
+	mu      sync.Mutex
+	next    int
+	retryAt []time.Time
+}
+
+// github.com/ivpusic/golog/appenders/roundrobin
+func (ra *RoundRobinAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/roundrobin"
+}
+
+func (ra *RoundRobinAppender) Append(log golog.Log) {
+	if err := ra.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will write log to next available appender. If it fails, other appenders are tried.
+// When all appenders are failed, all of them are tried, so log is not dropped
+// just because appenders are waiting for retry.
+// Error of last appender is returned if all of them fail.
+func (ra *RoundRobinAppender) TryAppend(log golog.Log) error {
+	if len(ra.appenders) == 0 {
+		return nil
+	}
+
+	// lock is not held while appenders are called,
+	// because they can report failed delivery from the same goroutine
+	ra.mu.Lock()
+	start := ra.next
+	ra.next = (ra.next + 1) % len(ra.appenders)
+	ra.mu.Unlock()
+
+	failed := ra.failed()
+
+	// available appenders are tried first, and then failed ones
+	var err error
+	for _, tryFailed := range []bool{false, true} {
+		for n := 0; n < len(ra.appenders); n++ {
+			i := (start + n) % len(ra.appenders)
+			if failed[i] != tryFailed {
+				continue
+			}
+
+			if err = tryAppend(ra.appenders[i], log); err == nil {
+				return nil
+			}
+
+			ra.skip(i)
+		}
+	}
+
+	return err
+}
+
+// Will return which appenders are waiting for retry, or know that their destination is unreachable.
+func (ra *RoundRobinAppender) failed() []bool {
+	now := time.Now()
+
+	ra.mu.Lock()
+	failed := make([]bool, len(ra.appenders))
+	for i, retryAt := range ra.retryAt {
+		failed[i] = now.Before(retryAt)
+	}
+	ra.mu.Unlock()
+
+	for i, appender := range ra.appenders {
+		failed[i] = failed[i] || deliveryFailed(appender)
+	}
+
+	return failed
+}
+
+// Will skip appender at index i for retry interval.
+func (ra *RoundRobinAppender) skip(i int) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	ra.retryAt[i] = time.Now().Add(ra.retry)
+}
+
+// Called when appender at index i cannot deliver logs.
+// Appender is skipped, and logs which it dropped are written to other available appenders.
+// If there is no available appender, logs are only reported by appender which dropped them.
+func (ra *RoundRobinAppender) undelivered(i int, logs []golog.Log) {
+	ra.skip(i)
+
+	failed := ra.failed()
+	for _, log := range logs {
+		var err error
+		for n := 1; n < len(ra.appenders); n++ {
+			j := (i + n) % len(ra.appenders)
+			if failed[j] {
+				continue
+			}
+
+			if err = tryAppend(ra.appenders[j], log); err == nil {
+				break
+			}
+
+			ra.skip(j)
+			failed[j] = true
+		}
+
+		if err != nil {
+			reportError(log, err)
+		}
+	}
+}
+
+// Will set how long appender which failed is skipped. Default is 30 seconds.
+func (ra *RoundRobinAppender) RetryInterval(interval time.Duration) *RoundRobinAppender {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	ra.retry = interval
+	return ra
+}
+
+// Will flush all appenders which support flushing.
+func (ra *RoundRobinAppender) Flush() error {
+	return flushAll(ra.appenders)
+}
+
+// Will close all appenders which support closing.
+func (ra *RoundRobinAppender) Close() error {
+	return closeAll(ra.appenders)
+}
+
+// Function for creating round-robin appender.
+func RoundRobin(appenders ...golog.Appender) *RoundRobinAppender {
+	ra := &RoundRobinAppender{
+		appenders: appenders,
+		retry:     30 * time.Second,
+		retryAt:   make([]time.Time, len(appenders)),
+	}
+
+	for i, appender := range ra.appenders {
+		i := i
+		watchDelivery(appender, func(_ error, logs []golog.Log) {
+			ra.undelivered(i, logs)
+		})
+	}
+
+	return ra
+}
@@ -0,0 +1,275 @@
+package appenders
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Appender which remembers logs, and fails while err is set
+type fakeAppender struct {
+	id string
+
+	mu      sync.Mutex
+	err     error
+	logs    []string
+	flushed bool
+	closed  bool
+}
+
+func (fa *fakeAppender) Id() string {
+	return fa.id
+}
+
+func (fa *fakeAppender) Append(log golog.Log) {
+	fa.TryAppend(log)
+}
+
+func (fa *fakeAppender) TryAppend(log golog.Log) error {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	if fa.err != nil {
+		return fa.err
+	}
+
+	fa.logs = append(fa.logs, log.Message)
+	return nil
+}
+
+func (fa *fakeAppender) Flush() error {
+	fa.flushed = true
+	return nil
+}
+
+func (fa *fakeAppender) Close() error {
+	fa.closed = true
+	return fa.err
+}
+
+func (fa *fakeAppender) fail(err error) {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	fa.err = err
+}
+
+func (fa *fakeAppender) received() []string {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	return append([]string{}, fa.logs...)
+}
+
+func TestFailoverNetworkPrimary(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	primary := Network(golog.Conf{"address": address, "backoff": "1h"})
+	secondary := &fakeAppender{}
+	appender := Failover(primary, secondary)
+
+	// network appender reports that it cannot connect, so secondary appender becomes active
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "first"}))
+
+	timeout := time.Now().Add(5 * time.Second)
+	for appender.Active() != 1 {
+		if time.Now().After(timeout) {
+			t.Fatal("secondary appender is not active")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// log kept in buffer of network appender is not written again
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "second"}))
+	assert.Equal(t, []string{"second"}, secondary.received())
+	assert.Nil(t, appender.Close())
+}
+
+func TestFailoverBatchingPrimary(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	primary := HTTP(golog.Conf{"url": srv.URL, "retries": "0", "flush_interval": "1h"})
+	primary.OnError(func(error, []golog.Log) {})
+	secondary := &fakeAppender{}
+	appender := Failover(primary, secondary)
+
+	// batch which cannot be sent is written to secondary appender, and it becomes active
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "first"}))
+	assert.Nil(t, appender.Flush())
+	assert.Equal(t, 1, appender.Active())
+
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "second"}))
+	assert.Equal(t, []string{"first", "second"}, secondary.received())
+	assert.Nil(t, appender.Close())
+}
+
+func TestFailover(t *testing.T) {
+	primary := &fakeAppender{}
+	secondary := &fakeAppender{}
+	appender := Failover(primary, secondary).ProbeInterval(50 * time.Millisecond)
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/failover", appender.Id())
+
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "first"}))
+
+	// secondary appender is used when primary fails
+	primary.fail(errors.New("primary failed"))
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "second"}))
+	assert.Equal(t, 1, appender.Active())
+
+	// primary appender is not tried before probe interval passes
+	primary.fail(nil)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "third"}))
+	assert.Equal(t, 1, appender.Active())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "fourth"}))
+	assert.Equal(t, 0, appender.Active())
+
+	assert.Equal(t, []string{"first", "fourth"}, primary.received())
+	assert.Equal(t, []string{"second", "third"}, secondary.received())
+}
+
+func TestFailoverAllFailed(t *testing.T) {
+	primary := &fakeAppender{}
+	secondary := &fakeAppender{}
+	appender := Failover(primary, secondary)
+
+	primary.fail(errors.New("primary failed"))
+	secondary.fail(errors.New("secondary failed"))
+	assert.Equal(t, "secondary failed", appender.TryAppend(golog.Log{Message: "first"}).Error())
+
+	// secondary appender is active, but primary one is tried when it fails
+	primary.fail(nil)
+	appender.TryAppend(golog.Log{Message: "second"})
+	secondary.fail(nil)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "third"}))
+	assert.Equal(t, 0, appender.Active())
+
+	assert.Equal(t, []string{"second", "third"}, primary.received())
+	assert.Nil(t, appender.Flush())
+	assert.True(t, primary.flushed)
+	assert.True(t, secondary.flushed)
+}
+
+func TestTee(t *testing.T) {
+	first := &fakeAppender{}
+	second := &fakeAppender{}
+	third := &fakeAppender{}
+
+	appender := Tee("myapp/alerts", first, second, third)
+	assert.Equal(t, "myapp/alerts", appender.Id())
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/tee", Tee("").Id())
+
+	// log is written to all appenders, even if some of them fail
+	second.fail(errors.New("second failed"))
+	assert.Equal(t, "second failed", appender.TryAppend(golog.Log{Message: "first"}).Error())
+	assert.Equal(t, []string{"first"}, first.received())
+	assert.Equal(t, []string{"first"}, third.received())
+
+	assert.Equal(t, "second failed", appender.Close().Error())
+	assert.True(t, first.closed)
+	assert.True(t, third.closed)
+}
+
+func TestTeeDisable(t *testing.T) {
+	first := &fakeAppender{}
+	second := &fakeAppender{}
+
+	logger := golog.GetLogger("github.com/ivpusic/golog/appenders/tee_test")
+	logger.Enable(Tee("myapp/group", first, second))
+	logger.Info("first")
+
+	logger.Disable("myapp/group")
+	logger.Info("second")
+
+	assert.Equal(t, []string{"first"}, first.received())
+	assert.Equal(t, []string{"first"}, second.received())
+}
+
+func TestRoundRobin(t *testing.T) {
+	first := &fakeAppender{}
+	second := &fakeAppender{}
+	third := &fakeAppender{}
+	appender := RoundRobin(first, second, third).RetryInterval(50 * time.Millisecond)
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/roundrobin", appender.Id())
+
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		assert.Nil(t, appender.TryAppend(golog.Log{Message: msg}))
+	}
+
+	assert.Equal(t, []string{"a", "d"}, first.received())
+	assert.Equal(t, []string{"b"}, second.received())
+	assert.Equal(t, []string{"c"}, third.received())
+
+	// failed appender is skipped until retry interval passes
+	second.fail(errors.New("second failed"))
+	for _, msg := range []string{"e", "f", "g"} {
+		assert.Nil(t, appender.TryAppend(golog.Log{Message: msg}))
+	}
+
+	assert.Equal(t, []string{"a", "d", "g"}, first.received())
+	assert.Equal(t, []string{"c", "e", "f"}, third.received())
+
+	second.fail(nil)
+	time.Sleep(60 * time.Millisecond)
+	for _, msg := range []string{"h", "i", "j"} {
+		assert.Nil(t, appender.TryAppend(golog.Log{Message: msg}))
+	}
+
+	assert.Equal(t, []string{"b", "h"}, second.received())
+}
+
+func TestRoundRobinAllFailed(t *testing.T) {
+	first := &fakeAppender{}
+	second := &fakeAppender{}
+	appender := RoundRobin(first, second)
+
+	first.fail(errors.New("first failed"))
+	second.fail(errors.New("second failed"))
+	assert.NotNil(t, appender.TryAppend(golog.Log{Message: "a"}))
+
+	// failed appenders are tried when no appender is available
+	first.fail(nil)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "b"}))
+	assert.Equal(t, []string{"b"}, first.received())
+
+	assert.Nil(t, RoundRobin().TryAppend(golog.Log{Message: "c"}))
+}
+
+func TestRoundRobinBatching(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	first := HTTP(golog.Conf{"url": srv.URL, "retries": "0", "flush_interval": "1h"})
+	first.OnError(func(error, []golog.Log) {})
+	second := &fakeAppender{}
+	appender := RoundRobin(first, second)
+
+	// batch which cannot be sent is written to other appender,
+	// and failed appender is skipped until retry interval passes
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "a"}))
+	assert.Nil(t, appender.Flush())
+
+	for _, msg := range []string{"b", "c"} {
+		assert.Nil(t, appender.TryAppend(golog.Log{Message: msg}))
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, second.received())
+	assert.Nil(t, appender.Close())
+}
@@ -0,0 +1,84 @@
+package appenders
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Helpers for reading typed values from appender configuration.
+// If value is missing default value is returned.
+// If value is invalid, problem is printed and default value is returned.
+
+func confString(cnf golog.Conf, key, def string) string {
+	if value, ok := cnf[key]; ok && len(value) > 0 {
+		return value
+	}
+
+	return def
+}
+
+func confInt(cnf golog.Conf, key string, def int) int {
+	value, ok := cnf[key]
+	if !ok || len(value) == 0 {
+		return def
+	}
+
+	num, err := strconv.Atoi(value)
+	if err != nil {
+		fmt.Println("invalid value of " + key + ": " + err.Error())
+		return def
+	}
+
+	return num
+}
+
+func confBool(cnf golog.Conf, key string, def bool) bool {
+	value, ok := cnf[key]
+	if !ok || len(value) == 0 {
+		return def
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		fmt.Println("invalid value of " + key + ": " + err.Error())
+		return def
+	}
+
+	return b
+}
+
+func confDuration(cnf golog.Conf, key string, def time.Duration) time.Duration {
+	value, ok := cnf[key]
+	if !ok || len(value) == 0 {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Println("invalid value of " + key + ": " + err.Error())
+		return def
+	}
+
+	return d
+}
+
+// Level can be configured using name of level, for example "WARN" or "error".
+func confLevel(cnf golog.Conf, key string, def golog.Level) golog.Level {
+	value, ok := cnf[key]
+	if !ok || len(value) == 0 {
+		return def
+	}
+
+	for _, lvl := range []golog.Level{golog.DEBUG, golog.INFO, golog.WARN, golog.ERROR, golog.PANIC} {
+		if strings.EqualFold(lvl.Name, value) {
+			return lvl
+		}
+	}
+
+	fmt.Println("invalid value of " + key + ": unknown level " + value)
+	return def
+}
@@ -0,0 +1,345 @@
+package appenders
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Template which is installed if template configuration key is set,
+// and template_file is not set. {{pattern}} is replaced with pattern of index names.
+const elasticTemplate = `{
+  "index_patterns": ["{{pattern}}"],
+  "template": {
+    "mappings": {
+      "properties": {
+        "@timestamp": {"type": "date"},
+        "message": {"type": "text"},
+        "level": {"type": "keyword"},
+        "logger": {"type": "keyword"},
+        "pid": {"type": "integer"}
+      }
+    }
+  }
+}`
+
+// Result of one document in bulk response.
+type elasticItem struct {
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error"`
+}
+
+type elasticBulkResponse struct {
+	Errors bool                     `json:"errors"`
+	Items  []map[string]elasticItem `json:"items"`
+}
+
+// Representing appender which indexes logs in Elasticsearch or OpenSearch using bulk API.
+// Logs are collected in batches, and sent from background goroutine.
+// Every log is indexed in index with date of log in name, for example logs-2026.10.18.
+//
+// Bulk request can partially fail. Documents rejected because cluster is overloaded
+// are sent again with exponential backoff, and other failed documents are reported
+// to error handler.
+type ElasticAppender struct {
+	url        string
+	index      string
+	dateLayout string
+	sender     *httpSender
+	batcher    *batcher
+
+	template     string
+	templateBody []byte
+
+	mu                sync.Mutex
+	templateInstalled bool
+}
+
+// github.com/ivpusic/golog/appenders/elasticsearch
+func (ea *ElasticAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/elasticsearch"
+}
+
+func (ea *ElasticAppender) Append(log golog.Log) {
+	if err := ea.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+func (ea *ElasticAppender) TryAppend(log golog.Log) error {
+	return ea.batcher.add(log)
+}
+
+// Indexing logs using one or more bulk requests.
+// Error is returned if some of logs cannot be indexed.
+func (ea *ElasticAppender) AppendBatch(logs []golog.Log) error {
+	_, err := ea.bulk(logs)
+	return err
+}
+
+// Will index logs, and return logs which cannot be indexed.
+func (ea *ElasticAppender) bulk(logs []golog.Log) ([]golog.Log, error) {
+	if err := ea.installTemplate(); err != nil {
+		return logs, err
+	}
+
+	total := len(logs)
+	var failed []golog.Log
+	var reason string
+
+	b := ea.sender.backoff
+	for attempt := 0; ; attempt++ {
+		retry, rejected, why, err := ea.send(logs)
+		if err != nil {
+			return append(failed, logs...), err
+		}
+
+		failed = append(failed, rejected...)
+		if len(rejected) > 0 || len(reason) == 0 {
+			reason = why
+		}
+
+		if len(retry) > 0 && attempt < ea.sender.retries {
+			time.Sleep(b.next())
+			logs = retry
+			continue
+		}
+
+		failed = append(failed, retry...)
+		if len(failed) == 0 {
+			return nil, nil
+		}
+
+		return failed, fmt.Errorf("elasticsearch: %d of %d documents are not indexed: %s", len(failed), total, reason)
+	}
+}
+
+// Making one bulk request. Returns logs which should be sent again,
+// logs which failed because of some other reason, and reason of first failure.
+func (ea *ElasticAppender) send(logs []golog.Log) ([]golog.Log, []golog.Log, string, error) {
+	body := &bytes.Buffer{}
+	for _, log := range logs {
+		doc, err := json.Marshal(ea.Document(log))
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		action, _ := json.Marshal(map[string]interface{}{
+			"create": map[string]string{"_index": ea.Index(log)},
+		})
+
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(doc)
+		body.WriteByte('\n')
+	}
+
+	respBody, err := ea.sender.send("POST", ea.url+"/_bulk", "application/x-ndjson", body.Bytes())
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	resp := elasticBulkResponse{}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, nil, "", fmt.Errorf("elasticsearch: invalid bulk response: %s", err.Error())
+	}
+
+	if !resp.Errors {
+		return nil, nil, "", nil
+	}
+
+	if len(resp.Items) != len(logs) {
+		return nil, nil, "", fmt.Errorf("elasticsearch: bulk response has %d items, expected %d", len(resp.Items), len(logs))
+	}
+
+	var retry, failed []golog.Log
+	var retryReason, failedReason string
+	for i, result := range resp.Items {
+		for _, item := range result {
+			switch {
+			case item.Status < 300:
+			case item.Status == 429 || item.Status >= 500:
+				// rejected because cluster is overloaded
+				retry = append(retry, logs[i])
+				if len(retryReason) == 0 {
+					retryReason = string(item.Error)
+				}
+			default:
+				failed = append(failed, logs[i])
+				if len(failedReason) == 0 {
+					failedReason = string(item.Error)
+				}
+			}
+		}
+	}
+
+	// reason of permanent failure is more interesting
+	if len(failedReason) > 0 {
+		return retry, failed, failedReason, nil
+	}
+
+	return retry, failed, retryReason, nil
+}
+
+// Will install index template before first bulk request.
+// If installation fails, it is tried again before next bulk request.
+func (ea *ElasticAppender) installTemplate() error {
+	if len(ea.template) == 0 {
+		return nil
+	}
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	if ea.templateInstalled {
+		return nil
+	}
+
+	_, err := ea.sender.send("PUT", ea.url+"/_index_template/"+ea.template, "application/json", ea.templateBody)
+	if err != nil {
+		return err
+	}
+
+	ea.templateInstalled = true
+	return nil
+}
+
+// Will return name of index in which log is indexed.
+func (ea *ElasticAppender) Index(log golog.Log) string {
+	if len(ea.dateLayout) == 0 {
+		return ea.index
+	}
+
+	timestamp := log.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return ea.index + "-" + timestamp.UTC().Format(ea.dateLayout)
+}
+
+// Will convert log to document which is indexed.
+func (ea *ElasticAppender) Document(log golog.Log) map[string]interface{} {
+	timestamp := log.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	doc := map[string]interface{}{
+		"@timestamp": timestamp.UTC().Format(time.RFC3339Nano),
+		"message":    log.Message,
+		"level":      log.Level.Name,
+		"pid":        log.Pid,
+	}
+
+	if name := loggerName(log); len(name) > 0 {
+		doc["logger"] = name
+	}
+
+	if len(log.Ctx) > 0 {
+		doc["ctx"] = log.Ctx
+	}
+
+	if len(log.Data) > 0 {
+		doc["data"] = log.Data
+	}
+
+	return doc
+}
+
+// Will set function which is called when logs cannot be indexed.
+// Only logs which are not indexed are passed to function.
+// By default errors are printed.
+func (ea *ElasticAppender) OnError(fn func(err error, logs []golog.Log)) *ElasticAppender {
+	ea.batcher.setErrorHandler(fn)
+	return ea
+}
+
+func (ea *ElasticAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	ea.batcher.watchers.add(fn)
+}
+
+// Will send current batch, and wait until all pending batches are sent.
+func (ea *ElasticAppender) Flush() error {
+	return ea.batcher.flush()
+}
+
+// Will send all collected logs and stop appender.
+func (ea *ElasticAppender) Close() error {
+	return ea.batcher.close()
+}
+
+// Function for creating Elasticsearch appender. It works with OpenSearch too.
+// Supported configuration keys are:
+// url - address of cluster (default http://127.0.0.1:9200)
+// index - index name, or prefix of index name if date is used (default logs)
+// index_date - Go time layout of date in index name, empty to disable (default 2006.01.02)
+// username, password - credentials for basic authentication
+// api_key - API key, sent in Authorization header
+// template - name of index template which is installed before first bulk request
+// template_file - path of JSON file with index template (default is simple template for index)
+// Batching, retries, headers, gzip and timeout are configured
+// using the same keys as in HTTP appender, but gzip is false by default.
+func Elastic(cnf golog.Conf) *ElasticAppender {
+	index := confString(cnf, "index", "logs")
+
+	dateLayout, ok := cnf["index_date"]
+	if !ok {
+		dateLayout = "2006.01.02"
+	}
+
+	sender := newHTTPSender(cnf, false)
+	if user := cnf["username"]; len(user) > 0 {
+		credentials := base64.StdEncoding.EncodeToString([]byte(user + ":" + cnf["password"]))
+		sender.headers.Set("Authorization", "Basic "+credentials)
+	}
+
+	if key := cnf["api_key"]; len(key) > 0 {
+		sender.headers.Set("Authorization", "ApiKey "+key)
+	}
+
+	ea := &ElasticAppender{
+		url:        strings.TrimRight(confString(cnf, "url", "http://127.0.0.1:9200"), "/"),
+		index:      index,
+		dateLayout: dateLayout,
+		sender:     sender,
+		template:   cnf["template"],
+	}
+
+	if len(ea.template) > 0 {
+		pattern := index
+		if len(dateLayout) > 0 {
+			pattern += "-*"
+		}
+
+		ea.templateBody = []byte(strings.Replace(elasticTemplate, "{{pattern}}", pattern, -1))
+
+		if path := cnf["template_file"]; len(path) > 0 {
+			body, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Println(err.Error())
+			} else {
+				ea.templateBody = body
+			}
+		}
+	}
+
+	// only logs which are not indexed are reported to error handler
+	ea.batcher = newBatcher(cnf, func(logs []golog.Log) error {
+		if failed, err := ea.bulk(logs); err != nil {
+			ea.batcher.reportError(err, failed)
+		}
+
+		return nil
+	})
+
+	return ea
+}
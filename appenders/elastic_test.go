@@ -0,0 +1,274 @@
+package appenders
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Stand-in for Elasticsearch, which remembers indexed documents.
+// Documents with message starting with "reject" are rejected with 429 once,
+// and documents with message starting with "invalid" are rejected with 400.
+type elasticServer struct {
+	mu        sync.Mutex
+	docs      map[string][]map[string]interface{}
+	bulks     int
+	templates map[string]string
+	headers   http.Header
+	rejected  map[string]bool
+}
+
+func newElasticServer() *elasticServer {
+	return &elasticServer{
+		docs:      map[string][]map[string]interface{}{},
+		templates: map[string]string{},
+		rejected:  map[string]bool{},
+	}
+}
+
+func (s *elasticServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.headers = r.Header
+
+	if strings.HasPrefix(r.URL.Path, "/_index_template/") {
+		body, _ := io.ReadAll(r.Body)
+		s.templates[strings.TrimPrefix(r.URL.Path, "/_index_template/")] = string(body)
+		w.Write([]byte(`{"acknowledged":true}`))
+		return
+	}
+
+	if r.URL.Path != "/_bulk" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.bulks++
+
+	resp := elasticBulkResponse{}
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		action := map[string]map[string]string{}
+		json.Unmarshal(scanner.Bytes(), &action)
+
+		scanner.Scan()
+		doc := map[string]interface{}{}
+		json.Unmarshal(scanner.Bytes(), &doc)
+
+		msg := doc["message"].(string)
+		item := elasticItem{Status: 201}
+
+		switch {
+		case strings.HasPrefix(msg, "reject") && !s.rejected[msg]:
+			s.rejected[msg] = true
+			item = elasticItem{Status: 429, Error: json.RawMessage(`{"type":"es_rejected_execution_exception"}`)}
+		case strings.HasPrefix(msg, "invalid"):
+			item = elasticItem{Status: 400, Error: json.RawMessage(`{"type":"mapper_parsing_exception"}`)}
+		default:
+			index := action["create"]["_index"]
+			s.docs[index] = append(s.docs[index], doc)
+		}
+
+		if item.Status >= 300 {
+			resp.Errors = true
+		}
+
+		resp.Items = append(resp.Items, map[string]elasticItem{"create": item})
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *elasticServer) indexed(index string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.docs[index]
+}
+
+func TestElasticId(t *testing.T) {
+	appender := Elastic(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/elasticsearch", appender.Id())
+}
+
+func TestElasticIndex(t *testing.T) {
+	appender := Elastic(golog.Conf{"index": "myapp"})
+	defer appender.Close()
+
+	log := golog.Log{Time: time.Date(2026, 10, 18, 23, 0, 0, 0, time.FixedZone("", -2*3600))}
+	assert.Equal(t, "myapp-2026.10.19", appender.Index(log))
+
+	appender = Elastic(golog.Conf{"index": "myapp", "index_date": ""})
+	defer appender.Close()
+
+	assert.Equal(t, "myapp", appender.Index(log))
+}
+
+func TestElasticBulk(t *testing.T) {
+	es := newElasticServer()
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	appender := Elastic(golog.Conf{
+		"url":      srv.URL + "/",
+		"username": "elastic",
+		"password": "secret",
+	})
+	defer appender.Close()
+
+	day := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	appender.Append(golog.Log{
+		Message: "first",
+		Level:   golog.INFO,
+		Time:    day,
+		Pid:     42,
+		Logger:  &golog.Logger{Name: "somelogger"},
+		Ctx:     golog.Ctx{"key": "value"},
+	})
+	appender.Append(golog.Log{Message: "second", Time: day.Add(24 * time.Hour)})
+	assert.Nil(t, appender.Flush())
+
+	docs := es.indexed("logs-2026.10.18")
+	assert.Len(t, docs, 1)
+	assert.Equal(t, "first", docs[0]["message"])
+	assert.Equal(t, "INFO", docs[0]["level"])
+	assert.Equal(t, "somelogger", docs[0]["logger"])
+	assert.Equal(t, "2026-10-18T12:00:00Z", docs[0]["@timestamp"])
+	assert.Equal(t, float64(42), docs[0]["pid"])
+	assert.Equal(t, map[string]interface{}{"key": "value"}, docs[0]["ctx"])
+
+	assert.Len(t, es.indexed("logs-2026.10.19"), 1)
+
+	user, password, ok := (&http.Request{Header: es.headers}).BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "elastic", user)
+	assert.Equal(t, "secret", password)
+}
+
+func TestElasticPartialFailure(t *testing.T) {
+	es := newElasticServer()
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	appender := Elastic(golog.Conf{
+		"url":        srv.URL,
+		"index_date": "",
+		"backoff":    "1ms",
+	})
+
+	var failed []golog.Log
+	var failure error
+	appender.OnError(func(err error, logs []golog.Log) {
+		failure = err
+		failed = append(failed, logs...)
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Append(golog.Log{Message: "rejected"})
+	appender.Append(golog.Log{Message: "invalid"})
+	assert.Nil(t, appender.Close())
+
+	// rejected document is sent again, and invalid one is reported
+	docs := es.indexed("logs")
+	assert.Len(t, docs, 2)
+	assert.Equal(t, "rejected", docs[1]["message"])
+	assert.Exactly(t, 2, es.bulks)
+
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "invalid", failed[0].Message)
+	assert.Contains(t, failure.Error(), "1 of 3 documents are not indexed")
+	assert.Contains(t, failure.Error(), "mapper_parsing_exception")
+}
+
+func TestElasticAppendBatch(t *testing.T) {
+	es := newElasticServer()
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	appender := Elastic(golog.Conf{
+		"url":        srv.URL,
+		"index_date": "",
+		"retries":    "0",
+	})
+	defer appender.Close()
+
+	// rejected documents are not sent again if there are no retries
+	err := appender.AppendBatch([]golog.Log{{Message: "first"}, {Message: "rejected"}})
+	assert.NotNil(t, err)
+	assert.Len(t, es.indexed("logs"), 1)
+
+	assert.Nil(t, appender.AppendBatch([]golog.Log{{Message: "rejected"}}))
+	assert.Len(t, es.indexed("logs"), 2)
+}
+
+func TestElasticTemplate(t *testing.T) {
+	es := newElasticServer()
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	appender := Elastic(golog.Conf{
+		"url":      srv.URL,
+		"index":    "myapp",
+		"template": "myapp-template",
+	})
+	defer appender.Close()
+
+	assert.Nil(t, appender.AppendBatch([]golog.Log{{Message: "first"}}))
+
+	template := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(es.templates["myapp-template"]), &template))
+	assert.Equal(t, []interface{}{"myapp-*"}, template["index_patterns"])
+
+	// custom template from file
+	path := filepath.Join(t.TempDir(), "template.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"index_patterns":["custom-*"]}`), 0644))
+
+	appender = Elastic(golog.Conf{
+		"url":           srv.URL,
+		"template":      "custom",
+		"template_file": path,
+	})
+	defer appender.Close()
+
+	assert.Nil(t, appender.AppendBatch([]golog.Log{{Message: "second"}}))
+	assert.Equal(t, `{"index_patterns":["custom-*"]}`, es.templates["custom"])
+}
+
+func TestElasticTemplateError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	appender := Elastic(golog.Conf{
+		"url":      srv.URL,
+		"template": "sometemplate",
+	})
+	defer appender.Close()
+
+	var failed []golog.Log
+	appender.OnError(func(err error, logs []golog.Log) {
+		failed = append(failed, logs...)
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Flush()
+
+	// logs are not sent if template cannot be installed
+	assert.Len(t, failed, 1)
+	assert.NotNil(t, appender.AppendBatch([]golog.Log{{Message: "second"}}))
+}
@@ -0,0 +1,56 @@
+package appenders
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Functions for encoding logs, which can be selected using encoding configuration key.
+var encodings = map[string]func(golog.Log) ([]byte, error){
+	"json": encodeJSON,
+	"text": encodeText,
+}
+
+// Will return encoding function with provided name.
+// If there is no such encoding, JSON encoding is returned.
+func encoding(name string) func(golog.Log) ([]byte, error) {
+	if encode, ok := encodings[name]; ok {
+		return encode
+	}
+
+	if len(name) > 0 {
+		fmt.Println("unknown encoding " + name + ", using json")
+	}
+
+	return encodeJSON
+}
+
+// Encoding log as JSON, in the same format as file appender.
+func encodeJSON(log golog.Log) ([]byte, error) {
+	return json.Marshal(log)
+}
+
+// Encoding log as single line of text, with context as key=value pairs.
+// For example `2006-01-02T15:04:05Z INFO logger some message key=value`
+func encodeText(log golog.Log) ([]byte, error) {
+	parts := []string{
+		log.Time.Format(time.RFC3339Nano),
+		log.Level.Name,
+	}
+
+	if name := loggerName(log); len(name) > 0 {
+		parts = append(parts, name)
+	}
+
+	parts = append(parts, strings.Replace(log.Message, "\n", "\\n", -1))
+
+	for _, k := range sortedKeys(log.Ctx) {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, log.Ctx[k]))
+	}
+
+	return []byte(strings.Join(parts, " ")), nil
+}
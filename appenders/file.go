@@ -1,14 +1,273 @@
 package appenders
 
 import (
+	"bytes"
+	"compress/gzip"
+	"container/list"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/ivpusic/golog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
 )
 
+const (
+	// file is synced after every log
+	FileSyncAlways = "always"
+
+	// file is synced periodically
+	FileSyncInterval = "interval"
+
+	// file is never synced, operating system decides when data is written to disk
+	FileSyncNever = "never"
+
+	// file is synced after logs with level ERROR or higher
+	FileSyncError = "error"
+)
+
+const (
+	// file is not locked, lines written by different processes can interleave
+	FileLockNone = "none"
+
+	// advisory lock of file is taken while writing, so lines of different processes don't interleave
+	FileLockFlock = "flock"
+
+	// every write is limited in size, relying on atomicity of appending to file,
+	// and lines which are longer than limit are rejected
+	FileLockAppend = "append"
+)
+
+const (
+	// logs are written to file as they are
+	FileCompressNone = "none"
+
+	// logs are written to file as gzip stream
+	FileCompressGzip = "gzip"
+)
+
+var (
+	errFileClosed      = errors.New("file: appender is closed")
+	errFileLineTooLong = errors.New("file: log is longer than atomic_size")
+)
+
+// Open file with write buffer. Buffer is always written on line boundaries,
+// so every write to file contains only whole lines.
+// Information about file is kept, so it can be detected that file is rotated.
+// If file is compressed, every buffer is compressed and written
+// as gzip flush point, so data can be decompressed up to the last write.
+type fileHandle struct {
+	path       string
+	file       *os.File
+	buf        []byte
+	bufferSize int
+	lock       string
+	info       os.FileInfo
+	size       int64
+	dirty      bool
+	lastCheck  time.Time
+	elem       *list.Element
+	gz         *gzip.Writer
+	zbuf       bytes.Buffer
+}
+
+func openFile(path string, mode os.FileMode, bufferSize int, lock string, atomicSize int) (*fileHandle, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// with append locking, buffer cannot be bigger than one atomic write
+	if lock == FileLockAppend && bufferSize > atomicSize {
+		bufferSize = atomicSize
+	}
+
+	return &fileHandle{
+		path:       path,
+		file:       f,
+		buf:        make([]byte, 0, bufferSize),
+		bufferSize: bufferSize,
+		lock:       lock,
+		info:       info,
+		size:       info.Size(),
+		lastCheck:  time.Now(),
+	}, nil
+}
+
+// Will add line to buffer. Buffer is written first if line doesn't fit in it.
+func (h *fileHandle) write(line []byte) error {
+	if len(h.buf)+len(line) > h.bufferSize {
+		if err := h.flush(); err != nil {
+			return err
+		}
+	}
+
+	h.buf = append(h.buf, line...)
+
+	if len(h.buf) >= h.bufferSize {
+		return h.flush()
+	}
+
+	return nil
+}
+
+// Checking whether file on path is not file which is open anymore,
+// because it is renamed or removed, or whether it is truncated.
+func (h *fileHandle) rotated() bool {
+	info, err := os.Stat(h.path)
+	if err != nil {
+		return true
+	}
+
+	if !os.SameFile(info, h.info) {
+		return true
+	}
+
+	return info.Size() < h.size
+}
+
+// Will start new gzip stream, which is written after existing content of file.
+// Concatenated gzip streams are read as one by gzip tools.
+func (h *fileHandle) compress(level int) error {
+	gz, err := gzip.NewWriterLevel(&h.zbuf, level)
+	if err != nil {
+		return err
+	}
+
+	h.gz = gz
+	return nil
+}
+
+// Will write buffered data to file, using one write.
+// Buffer is emptied even if writing fails, so lines are not written twice.
+// If writing fails, file has to be closed, because compressed stream cannot be continued,
+// and new stream is started when file is opened again.
+func (h *fileHandle) flush() error {
+	if len(h.buf) == 0 {
+		return nil
+	}
+
+	data := h.buf
+	if h.gz != nil {
+		h.zbuf.Reset()
+		_, err := h.gz.Write(h.buf)
+		if err == nil {
+			err = h.gz.Flush()
+		}
+
+		if err != nil {
+			h.buf = h.buf[:0]
+			return err
+		}
+
+		data = h.zbuf.Bytes()
+	}
+
+	if h.lock == FileLockFlock {
+		if err := lockFile(h.file); err != nil {
+			return err
+		}
+
+		defer unlockFile(h.file)
+	}
+
+	n, err := h.file.Write(data)
+	h.size += int64(n)
+	h.buf = h.buf[:0]
+	return err
+}
+
+// Will write end of gzip stream, so file can be read without errors.
+func (h *fileHandle) finish() error {
+	h.zbuf.Reset()
+	if err := h.gz.Close(); err != nil {
+		return err
+	}
+
+	n, err := h.file.Write(h.zbuf.Bytes())
+	h.size += int64(n)
+	return err
+}
+
+// Will write buffered data to file, and commit file to disk.
+func (h *fileHandle) sync() error {
+	if err := h.flush(); err != nil {
+		return err
+	}
+
+	return h.file.Sync()
+}
+
+func (h *fileHandle) close() error {
+	err := h.flush()
+	if err == nil && h.gz != nil {
+		err = h.finish()
+	}
+
+	if cerr := h.file.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// Representing appender which writes logs to file as JSON lines.
+// File is kept open, and logs are written through buffered writer.
+//
+// Path of file can contain placeholders, so logs can be written to different files
+// depending on logger, level and date. Missing directories are created,
+// and number of files which are open at the same time is limited,
+// so least recently used file is closed when limit is reached.
+//
+// How often data is written and committed to disk depends on sync policy.
+// With default policy, every log is written and synced immediately.
+// With other policies, buffered logs are written to file when buffer is full,
+// or when flush interval passes.
+//
+// Logs can be compressed as they are written, using gzip.
+// Buffered logs are compressed when they are written to file, so in case of crash,
+// only logs which are not written yet are lost. Stream is finished when file is closed.
+//
+// Multiple processes can write to the same file. Using lock option,
+// it can be guaranteed that lines written by different processes don't interleave.
+//
+// To work with logrotate, file is reopened when it is renamed, removed or truncated,
+// which is checked periodically before writing. Optionally, file is reopened
+// when process receives SIGHUP or SIGUSR1.
 type FileAppender struct {
-	path string
+	path          filePath
+	mode          os.FileMode
+	dirMode       os.FileMode
+	uid           int
+	gid           int
+	maxOpen       int
+	policy        string
+	bufferSize    int
+	syncInterval  time.Duration
+	flushInterval time.Duration
+	checkInterval time.Duration
+	lock          string
+	atomicSize    int
+	compress      string
+	compressLevel int
+
+	mu      sync.Mutex
+	handles map[string]*fileHandle
+	lru     *list.List
+	closed  bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
 }
 
 // github.com/ivpusic/golog/appender/file
@@ -17,32 +276,381 @@ func (fa *FileAppender) Id() string {
 }
 
 func (fa *FileAppender) Append(log golog.Log) {
-	f, err := os.OpenFile(fa.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err := fa.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will write log to file, and sync it if it is required by sync policy.
+// File is opened on first write. If writing fails, file is closed,
+// and it is opened again on next write.
+func (fa *FileAppender) TryAppend(log golog.Log) error {
+	line, err := json.Marshal(log)
 	if err != nil {
-		fmt.Println(err.Error())
-		if log.Logger.DoPanic {
-			panic(err)
+		return err
+	}
+
+	line = append(line, '\n')
+
+	// line would not be written atomically
+	if fa.lock == FileLockAppend && len(line) > fa.atomicSize {
+		return errFileLineTooLong
+	}
+
+	path := fa.path.expand(log)
+
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	if fa.closed {
+		return errFileClosed
+	}
+
+	h, err := fa.handle(path)
+	if err != nil {
+		return err
+	}
+
+	err = h.write(line)
+	if err == nil {
+		h.dirty = true
+		if fa.policy == FileSyncAlways || (fa.policy == FileSyncError && log.Level.Value >= golog.ERROR.Value) {
+			err = fa.sync(h)
+		}
+	}
+
+	if err != nil {
+		h.dirty = false
+		fa.closeHandle(h)
+	}
+
+	return err
+}
+
+// Will return open file for path. File is reopened if it is rotated,
+// and least recently used file is closed if too many files are open.
+// Caller has to hold lock.
+func (fa *FileAppender) handle(path string) (*fileHandle, error) {
+	if h, ok := fa.handles[path]; ok {
+		if fa.checkInterval <= 0 || time.Since(h.lastCheck) < fa.checkInterval {
+			fa.lru.MoveToFront(h.elem)
+			return h, nil
+		}
+
+		h.lastCheck = time.Now()
+		if !h.rotated() {
+			fa.lru.MoveToFront(h.elem)
+			return h, nil
+		}
+
+		if err := fa.closeHandle(h); err != nil {
+			fmt.Println(err.Error())
 		}
 	}
 
-	defer f.Close()
+	if err := fa.makeDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
 
-	line, err := json.Marshal(log)
+	_, err := os.Stat(path)
+	created := os.IsNotExist(err)
+
+	h, err := openFile(path, fa.mode, fa.bufferSize, fa.lock, fa.atomicSize)
 	if err != nil {
+		return nil, err
+	}
+
+	if fa.compress == FileCompressGzip {
+		if err := h.compress(fa.compressLevel); err != nil {
+			h.close()
+			return nil, err
+		}
+	}
+
+	if created {
+		if err := fa.chown(path); err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	h.elem = fa.lru.PushFront(h)
+	fa.handles[path] = h
+
+	for fa.lru.Len() > fa.maxOpen {
+		if err := fa.closeHandle(fa.lru.Back().Value.(*fileHandle)); err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	return h, nil
+}
+
+// Will write buffered logs and commit file to disk.
+// Caller has to hold lock.
+func (fa *FileAppender) sync(h *fileHandle) error {
+	if !h.dirty {
+		return nil
+	}
+
+	h.dirty = false
+	return h.sync()
+}
+
+// Will write buffered logs, sync and close file. Caller has to hold lock.
+func (fa *FileAppender) closeHandle(h *fileHandle) error {
+	delete(fa.handles, h.path)
+	fa.lru.Remove(h.elem)
+
+	err := fa.sync(h)
+	if cerr := h.close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// Will close all files, and return first error. Caller has to hold lock.
+func (fa *FileAppender) closeAll() error {
+	var first error
+	for _, h := range fa.handles {
+		if err := fa.closeHandle(h); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Will close files, and open them again on next write.
+// Buffered logs are written to old files.
+// It can be called after files are rotated, for example from signal handler.
+func (fa *FileAppender) Reopen() {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	if err := fa.closeAll(); err != nil {
 		fmt.Println(err.Error())
-		if log.Logger.DoPanic {
-			panic(err)
+	}
+}
+
+// Will reopen files when process receives one of signals.
+func (fa *FileAppender) handleSignals(signals []os.Signal) {
+	defer fa.wg.Done()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case <-c:
+			fa.Reopen()
+		case <-fa.done:
+			return
+		}
+	}
+}
+
+// Will write buffered logs to files, and sync files if sync policy is interval.
+func (fa *FileAppender) run() {
+	defer fa.wg.Done()
+
+	flush := time.NewTicker(fa.flushInterval)
+	defer flush.Stop()
+
+	var syncC <-chan time.Time
+	if fa.policy == FileSyncInterval {
+		ticker := time.NewTicker(fa.syncInterval)
+		defer ticker.Stop()
+		syncC = ticker.C
+	}
+
+	for {
+		var err error
+
+		select {
+		case <-flush.C:
+			fa.mu.Lock()
+			for _, h := range fa.handles {
+				if ferr := h.flush(); ferr != nil {
+					fa.closeHandle(h)
+					if err == nil {
+						err = ferr
+					}
+				}
+			}
+			fa.mu.Unlock()
+		case <-syncC:
+			err = fa.Flush()
+		case <-fa.done:
+			return
+		}
+
+		if err != nil {
+			fmt.Println(err.Error())
 		}
 	}
+}
 
-	line = append(line, byte('\n'))
+// Will write buffered logs and commit files to disk.
+// Files which cannot be written are closed, and they are opened again on next write.
+func (fa *FileAppender) Flush() error {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	var first error
+	for _, h := range fa.handles {
+		if err := fa.sync(h); err != nil {
+			fa.closeHandle(h)
+			if first == nil {
+				first = err
+			}
+		}
+	}
 
-	f.Write(line)
-	f.Sync()
+	return first
 }
 
+// Will write buffered logs, sync and close files.
+func (fa *FileAppender) Close() error {
+	fa.mu.Lock()
+	if fa.closed {
+		fa.mu.Unlock()
+		return nil
+	}
+
+	fa.closed = true
+	err := fa.closeAll()
+	fa.mu.Unlock()
+
+	close(fa.done)
+	fa.wg.Wait()
+	return err
+}
+
+// Function for creating file appender.
+// Supported configuration keys are:
+// path - path of file, which can contain placeholders {logger}, {level}, {date}, {date:layout} and {pid},
+// for example /var/log/app/{logger}/{date:2006-01-02}.{level}.log
+// mode - permissions of created files as octal number (default 0666, before umask)
+// dir_mode - permissions of created directories as octal number (default 0755, before umask)
+// owner, group - name or id of owner and group of created files and directories
+// max_open - max number of files which are open at the same time (default 16)
+// sync - always, interval, never or error, when file is committed to disk (default always)
+// sync_interval - time between syncs when sync policy is interval (default 1s)
+// flush_interval - max time before buffered logs are written to file, if sync policy is not always (default 1s)
+// buffer_size - size of write buffer in bytes (default 64KB)
+// reopen_check - how often it is checked whether file is rotated, 0 to disable (default 1s)
+// reopen_on_signal - reopen file when process receives SIGHUP or SIGUSR1 (default false)
+// lock - none, flock or append, how lines of different processes are kept from interleaving (default none)
+// atomic_size - max size of one write, and so max size of log, with append locking (default 4096)
+// compress - none or gzip, gzip cannot be used with lock (default none)
+// compress_level - gzip compression level from 0 to 9, or -1 for default level (default -1)
 func File(cnf golog.Conf) *FileAppender {
-	return &FileAppender{
-		path: cnf["path"],
+	policy := confString(cnf, "sync", FileSyncAlways)
+	switch policy {
+	case FileSyncAlways, FileSyncInterval, FileSyncNever, FileSyncError:
+	default:
+		fmt.Println("unknown file sync policy " + policy + ", using always")
+		policy = FileSyncAlways
+	}
+
+	lock := confString(cnf, "lock", FileLockNone)
+	switch lock {
+	case FileLockNone, FileLockAppend:
+	case FileLockFlock:
+		if !fileLockSupported {
+			fmt.Println("file locking is not supported, using append")
+			lock = FileLockAppend
+		}
+	default:
+		fmt.Println("unknown file lock " + lock + ", using none")
+		lock = FileLockNone
+	}
+
+	compress := confString(cnf, "compress", FileCompressNone)
+	switch compress {
+	case FileCompressNone:
+	case FileCompressGzip:
+		// streams of different processes would be mixed
+		if lock != FileLockNone {
+			fmt.Println("file compression cannot be used with lock, using none")
+			compress = FileCompressNone
+		}
+	default:
+		fmt.Println("unknown file compression " + compress + ", using none")
+		compress = FileCompressNone
 	}
+
+	compressLevel := confInt(cnf, "compress_level", gzip.DefaultCompression)
+	if compressLevel < gzip.DefaultCompression || compressLevel > gzip.BestCompression {
+		fmt.Println("invalid file compression level, using default")
+		compressLevel = gzip.DefaultCompression
+	}
+
+	atomicSize := confInt(cnf, "atomic_size", 4096)
+	if atomicSize <= 0 {
+		atomicSize = 4096
+	}
+
+	bufferSize := confInt(cnf, "buffer_size", 64*1024)
+	if bufferSize <= 0 {
+		bufferSize = 64 * 1024
+	}
+
+	maxOpen := confInt(cnf, "max_open", 16)
+	if maxOpen <= 0 {
+		maxOpen = 16
+	}
+
+	uid, err := fileOwnerId(cnf["owner"], lookupUser)
+	if err != nil {
+		fmt.Println("invalid value of owner: " + err.Error())
+	}
+
+	gid, err := fileOwnerId(cnf["group"], lookupGroup)
+	if err != nil {
+		fmt.Println("invalid value of group: " + err.Error())
+	}
+
+	fa := &FileAppender{
+		path:          parseFilePath(cnf["path"]),
+		mode:          confFileMode(cnf, "mode", 0666),
+		dirMode:       confFileMode(cnf, "dir_mode", 0755),
+		uid:           uid,
+		gid:           gid,
+		maxOpen:       maxOpen,
+		policy:        policy,
+		bufferSize:    bufferSize,
+		syncInterval:  confDuration(cnf, "sync_interval", time.Second),
+		flushInterval: confDuration(cnf, "flush_interval", time.Second),
+		checkInterval: confDuration(cnf, "reopen_check", time.Second),
+		lock:          lock,
+		atomicSize:    atomicSize,
+		compress:      compress,
+		compressLevel: compressLevel,
+		handles:       map[string]*fileHandle{},
+		lru:           list.New(),
+		done:          make(chan struct{}),
+	}
+
+	if fa.syncInterval <= 0 {
+		fa.syncInterval = time.Second
+	}
+
+	if fa.flushInterval <= 0 {
+		fa.flushInterval = time.Second
+	}
+
+	if policy != FileSyncAlways {
+		fa.wg.Add(1)
+		go fa.run()
+	}
+
+	if confBool(cnf, "reopen_on_signal", false) && len(fileReopenSignals) > 0 {
+		fa.wg.Add(1)
+		go fa.handleSignals(fileReopenSignals)
+	}
+
+	return fa
 }
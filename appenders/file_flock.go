@@ -0,0 +1,19 @@
+//go:build unix && !solaris && !aix
+
+package appenders
+
+import (
+	"os"
+	"syscall"
+)
+
+// Will take exclusive advisory lock of file, waiting until other processes release it.
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+const fileLockSupported = true
@@ -0,0 +1,22 @@
+//go:build !unix || solaris || aix
+
+package appenders
+
+import (
+	"errors"
+	"os"
+)
+
+// Flock is not available on Windows, Solaris, AIX and systems which are not unix,
+// so append locking is used instead.
+var errFileLockUnsupported = errors.New("file: locking is not supported on this platform")
+
+func lockFile(f *os.File) error {
+	return errFileLockUnsupported
+}
+
+func unlockFile(f *os.File) error {
+	return errFileLockUnsupported
+}
+
+const fileLockSupported = false
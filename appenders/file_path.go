@@ -0,0 +1,222 @@
+package appenders
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Part of file path, which is literal text or placeholder.
+type filePathPart struct {
+	literal     string
+	placeholder string
+	arg         string
+}
+
+// Path of file which can contain placeholders, expanded for every log:
+// {logger} - name of logger, with characters which are not safe in file names replaced
+// {level} - name of level in lowercase
+// {date} or {date:layout} - time of log formatted using layout (default 2006-01-02)
+// {pid} - id of process
+// Unknown placeholders are kept as they are.
+type filePath struct {
+	parts  []filePathPart
+	static bool
+}
+
+func parseFilePath(path string) filePath {
+	p := filePath{static: true}
+
+	for len(path) > 0 {
+		start := strings.Index(path, "{")
+		end := -1
+		if start >= 0 {
+			end = strings.Index(path[start:], "}")
+		}
+
+		if end < 0 {
+			p.parts = append(p.parts, filePathPart{literal: path})
+			break
+		}
+
+		end += start
+
+		if start > 0 {
+			p.parts = append(p.parts, filePathPart{literal: path[:start]})
+		}
+
+		name, arg := path[start+1:end], ""
+		if i := strings.Index(name, ":"); i >= 0 {
+			name, arg = name[:i], name[i+1:]
+		}
+
+		switch name {
+		case "logger", "level", "date", "pid":
+			p.parts = append(p.parts, filePathPart{placeholder: name, arg: arg})
+			p.static = false
+		default:
+			p.parts = append(p.parts, filePathPart{literal: path[start : end+1]})
+		}
+
+		path = path[end+1:]
+	}
+
+	return p
+}
+
+// Will return path of file for log.
+func (p filePath) expand(log golog.Log) string {
+	if p.static && len(p.parts) == 1 {
+		return p.parts[0].literal
+	}
+
+	path := &strings.Builder{}
+	for _, part := range p.parts {
+		switch part.placeholder {
+		case "":
+			path.WriteString(part.literal)
+		case "logger":
+			path.WriteString(fileSafeName(loggerName(log)))
+		case "level":
+			path.WriteString(fileSafeName(strings.ToLower(log.Level.Name)))
+		case "date":
+			layout := part.arg
+			if len(layout) == 0 {
+				layout = "2006-01-02"
+			}
+
+			timestamp := log.Time
+			if timestamp.IsZero() {
+				timestamp = time.Now()
+			}
+
+			path.WriteString(timestamp.Format(layout))
+		case "pid":
+			path.WriteString(strconv.Itoa(log.Pid))
+		}
+	}
+
+	return path.String()
+}
+
+// Will make name which can be used as one element of path.
+// Logger names are often paths of packages, so separators are replaced,
+// and names cannot point to parent directory.
+func fileSafeName(name string) string {
+	if len(name) == 0 {
+		return "default"
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	if name == "." || name == ".." {
+		return "_"
+	}
+
+	return name
+}
+
+// Will create directory of file with all missing parents,
+// and change owner of created directories if it is configured.
+func (fa *FileAppender) makeDir(dir string) error {
+	missing := []string{}
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+
+		missing = append(missing, d)
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, fa.dirMode); err != nil {
+		return err
+	}
+
+	for _, d := range missing {
+		if err := fa.chown(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fa *FileAppender) chown(path string) error {
+	if fa.uid < 0 && fa.gid < 0 {
+		return nil
+	}
+
+	return os.Chown(path, fa.uid, fa.gid)
+}
+
+// Will return id of user or group, which is configured using name or number.
+// If it is not configured, -1 is returned, so owner is not changed.
+func fileOwnerId(value string, lookup func(string) (string, error)) (int, error) {
+	if len(value) == 0 {
+		return -1, nil
+	}
+
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+
+	id, err := lookup(value)
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.Atoi(id)
+}
+
+func lookupUser(name string) (string, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	return u.Uid, nil
+}
+
+func lookupGroup(name string) (string, error) {
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return "", err
+	}
+
+	return g.Gid, nil
+}
+
+// File mode is configured as octal number, for example 0640.
+func confFileMode(cnf golog.Conf, key string, def os.FileMode) os.FileMode {
+	value, ok := cnf[key]
+	if !ok || len(value) == 0 {
+		return def
+	}
+
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		fmt.Println("invalid value of " + key + ": " + err.Error())
+		return def
+	}
+
+	return os.FileMode(mode) & os.ModePerm
+}
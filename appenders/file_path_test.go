@@ -0,0 +1,77 @@
+package appenders
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilePathExpand(t *testing.T) {
+	log := golog.Log{
+		Time:   time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		Level:  golog.WARN,
+		Logger: &golog.Logger{Name: "github.com/some/pkg  "},
+		Pid:    42,
+	}
+
+	path := parseFilePath("/var/log/{logger}/{date}.{level}.log")
+	assert.False(t, path.static)
+	assert.Equal(t, "/var/log/github.com_some_pkg/2024-03-05.warn.log", path.expand(log))
+
+	path = parseFilePath("/var/log/{date:2006/01}/app-{pid}.log")
+	assert.Equal(t, "/var/log/2024/03/app-42.log", path.expand(log))
+
+	// unknown and unclosed placeholders are kept
+	path = parseFilePath("/var/log/{unknown}/{logger")
+	assert.True(t, path.static)
+	assert.Equal(t, "/var/log/{unknown}/{logger", path.expand(log))
+
+	path = parseFilePath("log.txt")
+	assert.Equal(t, "log.txt", path.expand(log))
+}
+
+func TestFileSafeName(t *testing.T) {
+	assert.Equal(t, "default", fileSafeName(""))
+	assert.Equal(t, "app", fileSafeName("app"))
+	assert.Equal(t, "a_b_c_d", fileSafeName(`a/b\c:d`))
+	assert.Equal(t, "_", fileSafeName(".."))
+	assert.Equal(t, "_", fileSafeName("."))
+	assert.Equal(t, "line_break", fileSafeName("line\nbreak"))
+}
+
+func TestConfFileMode(t *testing.T) {
+	assert.Equal(t, os.FileMode(0640), confFileMode(golog.Conf{"mode": "0640"}, "mode", 0666))
+	assert.Equal(t, os.FileMode(0600), confFileMode(golog.Conf{"mode": "600"}, "mode", 0666))
+	assert.Equal(t, os.FileMode(0666), confFileMode(golog.Conf{"mode": "rw"}, "mode", 0666))
+	assert.Equal(t, os.FileMode(0666), confFileMode(golog.Conf{}, "mode", 0666))
+}
+
+func TestFileOwnerId(t *testing.T) {
+	lookup := func(name string) (string, error) {
+		if name == "logs" {
+			return "1001", nil
+		}
+
+		return "", errors.New("unknown " + name)
+	}
+
+	id, err := fileOwnerId("", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, id)
+
+	id, err = fileOwnerId("1000", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, id)
+
+	id, err = fileOwnerId("logs", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, 1001, id)
+
+	id, err = fileOwnerId("missing", lookup)
+	assert.NotNil(t, err)
+	assert.Equal(t, -1, id)
+}
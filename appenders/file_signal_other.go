@@ -0,0 +1,8 @@
+//go:build !unix
+
+package appenders
+
+import "os"
+
+// Reopening files on signals is supported only on unix systems
+var fileReopenSignals []os.Signal
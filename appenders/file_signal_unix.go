@@ -0,0 +1,11 @@
+//go:build unix
+
+package appenders
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals which are sent by logrotate to make process reopen its log files
+var fileReopenSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}
@@ -1,12 +1,19 @@
 package appenders
 
 import (
+	"bufio"
+	"compress/gzip"
 	"encoding/json"
 	"github.com/ivpusic/golog"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func init() {
@@ -48,3 +55,356 @@ func TestFileAppend(t *testing.T) {
 	err = json.Unmarshal(content, &logInstance)
 	assert.Equal(t, logtext, logInstance.Message)
 }
+
+// Will read lines of file, and check that every line is valid log.
+// Files with .gz extension are decompressed.
+func readLogLines(t *testing.T, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if !assert.Nil(t, err) {
+			return nil
+		}
+
+		r = gz
+	}
+
+	messages := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		log := golog.Log{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &log))
+		messages = append(messages, log.Message)
+	}
+
+	assert.Nil(t, scanner.Err())
+	return messages
+}
+
+func TestFileSyncNever(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":           path,
+		"sync":           FileSyncNever,
+		"flush_interval": "1h",
+	})
+
+	// logs are kept in buffer until flush
+	appender.Append(golog.Log{Message: "first"})
+	assert.Len(t, readLogLines(t, path), 0)
+
+	assert.Nil(t, appender.Flush())
+	assert.Equal(t, []string{"first"}, readLogLines(t, path))
+
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Close())
+	assert.Equal(t, []string{"first", "second"}, readLogLines(t, path))
+
+	assert.Equal(t, errFileClosed, appender.TryAppend(golog.Log{Message: "third"}))
+}
+
+func TestFileFlushInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":           path,
+		"sync":           FileSyncInterval,
+		"sync_interval":  "10ms",
+		"flush_interval": "10ms",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []string{"first"}, readLogLines(t, path))
+}
+
+func TestFileSyncError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":           path,
+		"sync":           FileSyncError,
+		"flush_interval": "1h",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first", Level: golog.INFO})
+	assert.Len(t, readLogLines(t, path), 0)
+
+	// buffered logs are written together with error
+	appender.Append(golog.Log{Message: "second", Level: golog.ERROR})
+	assert.Equal(t, []string{"first", "second"}, readLogLines(t, path))
+}
+
+func TestFileConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":        path,
+		"sync":        FileSyncNever,
+		"buffer_size": "4096",
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				appender.Append(golog.Log{Message: strings.Repeat("a", 1000)})
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Nil(t, appender.Close())
+	assert.Len(t, readLogLines(t, path), 1000)
+}
+
+func TestFileError(t *testing.T) {
+	// directory cannot be created, because there is file on its path
+	parent := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, ioutil.WriteFile(parent, nil, 0666))
+
+	appender := File(golog.Conf{
+		"path": filepath.Join(parent, "missing", "log.txt"),
+	})
+
+	assert.NotNil(t, appender.TryAppend(golog.Log{Message: "first"}))
+	assert.Nil(t, appender.Close())
+}
+
+func TestFileRotateRename(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.txt")
+	appender := File(golog.Conf{
+		"path":         path,
+		"reopen_check": "10ms",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+
+	// logrotate create mode renames file, and new file is made
+	assert.Nil(t, os.Rename(path, path+".1"))
+	appender.Append(golog.Log{Message: "second"})
+	time.Sleep(20 * time.Millisecond)
+	appender.Append(golog.Log{Message: "third"})
+
+	assert.Equal(t, []string{"first", "second"}, readLogLines(t, path+".1"))
+	assert.Equal(t, []string{"third"}, readLogLines(t, path))
+}
+
+func TestFileRotateTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":         path,
+		"sync":         FileSyncNever,
+		"reopen_check": "10ms",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Flush())
+
+	// logrotate copytruncate mode copies file, and truncates it
+	assert.Nil(t, os.Truncate(path, 0))
+	time.Sleep(20 * time.Millisecond)
+
+	appender.Append(golog.Log{Message: "third"})
+	assert.Nil(t, appender.Flush())
+	assert.Equal(t, []string{"third"}, readLogLines(t, path))
+
+	// buffered logs are not considered as truncation
+	appender.mu.Lock()
+	assert.False(t, appender.handles[path].rotated())
+	appender.mu.Unlock()
+}
+
+func TestFileReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":         path,
+		"reopen_check": "0",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+	assert.Nil(t, os.Rename(path, path+".1"))
+
+	// file is not checked, so it is reopened only when requested
+	time.Sleep(10 * time.Millisecond)
+	appender.Append(golog.Log{Message: "second"})
+	appender.Reopen()
+	appender.Append(golog.Log{Message: "third"})
+
+	assert.Equal(t, []string{"first", "second"}, readLogLines(t, path+".1"))
+	assert.Equal(t, []string{"third"}, readLogLines(t, path))
+}
+
+func TestFileAppendLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":        path,
+		"sync":        FileSyncNever,
+		"lock":        FileLockAppend,
+		"atomic_size": "512",
+	})
+
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "first"}))
+	assert.Equal(t, errFileLineTooLong, appender.TryAppend(golog.Log{Message: strings.Repeat("a", 512)}))
+
+	// buffer is not bigger than one atomic write
+	appender.mu.Lock()
+	assert.Equal(t, 512, appender.handles[path].bufferSize)
+	appender.mu.Unlock()
+
+	assert.Nil(t, appender.Close())
+	assert.Equal(t, []string{"first"}, readLogLines(t, path))
+}
+
+func TestFileTemplatePath(t *testing.T) {
+	dir := t.TempDir()
+	appender := File(golog.Conf{
+		"path": filepath.Join(dir, "{logger}", "{date:2006-01-02}.{level}.log"),
+	})
+
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	appender.Append(golog.Log{Time: now, Message: "first", Level: golog.INFO, Logger: &golog.Logger{Name: "app/db"}})
+	appender.Append(golog.Log{Time: now, Message: "second", Level: golog.ERROR, Logger: &golog.Logger{Name: "app/db"}})
+	appender.Append(golog.Log{Time: now, Message: "third", Level: golog.INFO, Logger: &golog.Logger{Name: "../web"}})
+	assert.Nil(t, appender.Close())
+
+	// directories are created, and logger names cannot escape directory
+	assert.Equal(t, []string{"first"}, readLogLines(t, filepath.Join(dir, "app_db", "2024-03-05.info.log")))
+	assert.Equal(t, []string{"second"}, readLogLines(t, filepath.Join(dir, "app_db", "2024-03-05.error.log")))
+	assert.Equal(t, []string{"third"}, readLogLines(t, filepath.Join(dir, ".._web", "2024-03-05.info.log")))
+}
+
+func TestFileMaxOpen(t *testing.T) {
+	dir := t.TempDir()
+	appender := File(golog.Conf{
+		"path":     filepath.Join(dir, "{level}.log"),
+		"sync":     FileSyncNever,
+		"max_open": "2",
+	})
+
+	appender.Append(golog.Log{Message: "first", Level: golog.INFO})
+	appender.Append(golog.Log{Message: "second", Level: golog.WARN})
+	appender.Append(golog.Log{Message: "third", Level: golog.INFO})
+	appender.Append(golog.Log{Message: "fourth", Level: golog.ERROR})
+
+	// least recently used file is closed, and its buffered logs are written
+	appender.mu.Lock()
+	assert.Len(t, appender.handles, 2)
+	assert.NotNil(t, appender.handles[filepath.Join(dir, "info.log")])
+	assert.NotNil(t, appender.handles[filepath.Join(dir, "error.log")])
+	appender.mu.Unlock()
+	assert.Equal(t, []string{"second"}, readLogLines(t, filepath.Join(dir, "warn.log")))
+
+	appender.Append(golog.Log{Message: "fifth", Level: golog.WARN})
+	assert.Nil(t, appender.Close())
+
+	assert.Equal(t, []string{"first", "third"}, readLogLines(t, filepath.Join(dir, "info.log")))
+	assert.Equal(t, []string{"second", "fifth"}, readLogLines(t, filepath.Join(dir, "warn.log")))
+	assert.Equal(t, []string{"fourth"}, readLogLines(t, filepath.Join(dir, "error.log")))
+}
+
+func TestFileGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.gz")
+	appender := File(golog.Conf{
+		"path":           path,
+		"sync":           FileSyncNever,
+		"flush_interval": "1h",
+		"compress":       FileCompressGzip,
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Flush())
+
+	// flushed logs can be read before stream is finished
+	f, err := os.Open(path)
+	assert.Nil(t, err)
+	gz, err := gzip.NewReader(f)
+	assert.Nil(t, err)
+	content, err := ioutil.ReadAll(gz)
+	f.Close()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, 2, strings.Count(string(content), "\n"))
+
+	assert.Nil(t, appender.Close())
+	assert.Equal(t, []string{"first", "second"}, readLogLines(t, path))
+
+	// new stream is appended to existing file
+	appender = File(golog.Conf{
+		"path":     path,
+		"compress": FileCompressGzip,
+	})
+
+	appender.Append(golog.Log{Message: "third"})
+	assert.Nil(t, appender.Close())
+	assert.Equal(t, []string{"first", "second", "third"}, readLogLines(t, path))
+}
+
+func TestFileGzipWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.gz")
+	appender := File(golog.Conf{
+		"path":           path,
+		"sync":           FileSyncNever,
+		"flush_interval": "1h",
+		"compress":       FileCompressGzip,
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+
+	appender.mu.Lock()
+	appender.handles[path].file.Close()
+	appender.mu.Unlock()
+
+	// file which cannot be written is closed, and new stream is started on next write
+	assert.NotNil(t, appender.Flush())
+	assert.Len(t, appender.handles, 0)
+
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Close())
+	assert.Equal(t, []string{"second"}, readLogLines(t, path))
+}
+
+func TestFileGzipRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.gz")
+	appender := File(golog.Conf{
+		"path":         path,
+		"compress":     FileCompressGzip,
+		"reopen_check": "0",
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+	rotated := filepath.Join(filepath.Dir(path), "log.1.gz")
+	assert.Nil(t, os.Rename(path, rotated))
+	appender.Reopen()
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Close())
+
+	// stream in rotated file is finished too
+	assert.Equal(t, []string{"first"}, readLogLines(t, rotated))
+	assert.Equal(t, []string{"second"}, readLogLines(t, path))
+}
+
+func TestFileGzipConf(t *testing.T) {
+	appender := File(golog.Conf{"compress": FileCompressGzip, "lock": FileLockAppend})
+	assert.Equal(t, FileCompressNone, appender.compress)
+	assert.Nil(t, appender.Close())
+
+	appender = File(golog.Conf{"compress": FileCompressGzip, "compress_level": "12"})
+	assert.Equal(t, FileCompressGzip, appender.compress)
+	assert.Equal(t, gzip.DefaultCompression, appender.compressLevel)
+	assert.Nil(t, appender.Close())
+}
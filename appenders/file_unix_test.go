@@ -0,0 +1,111 @@
+//go:build unix
+
+package appenders
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "{level}.log")
+	appender := File(golog.Conf{
+		"path":     path,
+		"mode":     "0600",
+		"dir_mode": "0700",
+		"owner":    strconv.Itoa(os.Getuid()),
+		"group":    strconv.Itoa(os.Getgid()),
+	})
+
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "first", Level: golog.INFO}))
+	assert.Nil(t, appender.Close())
+
+	info, err := os.Stat(filepath.Join(dir, "logs"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	info, err = os.Stat(filepath.Join(dir, "logs", "info.log"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFileReopenOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	appender := File(golog.Conf{
+		"path":             path,
+		"reopen_check":     "0",
+		"reopen_on_signal": "true",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+	assert.Nil(t, os.Rename(path, path+".1"))
+
+	// signal handler has to be registered before signal is sent
+	time.Sleep(20 * time.Millisecond)
+	assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	time.Sleep(50 * time.Millisecond)
+
+	appender.Append(golog.Log{Message: "second"})
+	assert.Equal(t, []string{"first"}, readLogLines(t, path+".1"))
+	assert.Equal(t, []string{"second"}, readLogLines(t, path))
+}
+
+// Not a real test. It is run as separate process by TestFileMultiProcess,
+// and it writes logs to file from environment.
+func TestFileMultiProcessHelper(t *testing.T) {
+	path := os.Getenv("GOLOG_FILE_HELPER_PATH")
+	if len(path) == 0 {
+		t.Skip("run by TestFileMultiProcess")
+	}
+
+	appender := File(golog.Conf{
+		"path":        path,
+		"sync":        FileSyncNever,
+		"lock":        os.Getenv("GOLOG_FILE_HELPER_LOCK"),
+		"buffer_size": "16384",
+	})
+
+	for i := 0; i < 200; i++ {
+		// lines of different sizes, so they cross buffer boundaries
+		msg := strconv.Itoa(os.Getpid()) + " " + strings.Repeat("a", 1000+(i%3)*1000)
+		if err := appender.TryAppend(golog.Log{Message: msg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileMultiProcess(t *testing.T) {
+	for _, lock := range []string{FileLockFlock, FileLockAppend} {
+		path := filepath.Join(t.TempDir(), "log.txt")
+
+		cmds := []*exec.Cmd{}
+		for i := 0; i < 4; i++ {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFileMultiProcessHelper$")
+			cmd.Env = append(os.Environ(), "GOLOG_FILE_HELPER_PATH="+path, "GOLOG_FILE_HELPER_LOCK="+lock)
+			assert.Nil(t, cmd.Start())
+			cmds = append(cmds, cmd)
+		}
+
+		for _, cmd := range cmds {
+			assert.Nil(t, cmd.Wait())
+		}
+
+		// every line has to be whole log
+		assert.Len(t, readLogLines(t, path), 800, lock)
+	}
+}
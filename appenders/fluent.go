@@ -0,0 +1,364 @@
+package appenders
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+const (
+	// Every message contains array of entries
+	FluentForward = "forward"
+
+	// Every message contains entries as one binary value
+	FluentPackedForward = "packed"
+)
+
+var (
+	fluentTagInvalid = regexp.MustCompile(`[^a-zA-Z0-9_\.\-]`)
+
+	errFluentAck = errors.New("fluent: invalid ack response")
+)
+
+// Representing appender which sends logs to Fluentd or Fluent Bit using forward protocol.
+// Logs are collected in batches, and sent from background goroutine.
+// Logs of one logger are sent in one message, with tag made from logger name.
+//
+// If ack is enabled, appender waits for server to confirm every message,
+// and sends message again if it is not confirmed. In that case logs are
+// delivered at least once, as long as they can be sent after configured retries.
+type FluentAppender struct {
+	network    string
+	address    string
+	tls        *tls.Config
+	timeout    time.Duration
+	ackTimeout time.Duration
+	tagPrefix  string
+	mode       string
+	ack        bool
+	eventTime  bool
+	retries    int
+	backoff    backoff
+	batcher    *batcher
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// github.com/ivpusic/golog/appenders/fluent
+func (fa *FluentAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/fluent"
+}
+
+func (fa *FluentAppender) Append(log golog.Log) {
+	if err := fa.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+func (fa *FluentAppender) TryAppend(log golog.Log) error {
+	return fa.batcher.add(log)
+}
+
+// Sending logs, one message for every tag.
+// If some messages are already sent when sending fails,
+// only logs which are not sent are reported to error handler, and nil is returned.
+func (fa *FluentAppender) AppendBatch(logs []golog.Log) error {
+	tags := []string{}
+	entries := map[string][]golog.Log{}
+
+	for _, log := range logs {
+		tag := fa.Tag(log)
+		if _, ok := entries[tag]; !ok {
+			tags = append(tags, tag)
+		}
+
+		entries[tag] = append(entries[tag], log)
+	}
+
+	for i, tag := range tags {
+		err := fa.send(tag, entries[tag])
+		if err == nil {
+			continue
+		}
+
+		if i == 0 {
+			return err
+		}
+
+		failed := []golog.Log{}
+		for _, rest := range tags[i:] {
+			failed = append(failed, entries[rest]...)
+		}
+
+		fa.batcher.reportError(err, failed)
+		return nil
+	}
+
+	return nil
+}
+
+// Will return tag of log, made from tag prefix and logger name.
+func (fa *FluentAppender) Tag(log golog.Log) string {
+	name := loggerName(log)
+	name = fluentTagInvalid.ReplaceAllString(strings.Replace(name, "/", ".", -1), "_")
+
+	switch {
+	case len(name) == 0 && len(fa.tagPrefix) == 0:
+		return "golog"
+	case len(name) == 0:
+		return fa.tagPrefix
+	case len(fa.tagPrefix) == 0:
+		return name
+	}
+
+	return fa.tagPrefix + "." + name
+}
+
+// Will send one message, and wait for ack if it is enabled.
+// Message is sent again after exponential backoff if it cannot be sent,
+// or if it is not confirmed.
+func (fa *FluentAppender) send(tag string, logs []golog.Log) error {
+	var chunk string
+	if fa.ack {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+
+		chunk = base64.StdEncoding.EncodeToString(id)
+	}
+
+	msg := fa.message(tag, logs, chunk)
+
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	b := fa.backoff
+	for attempt := 0; ; attempt++ {
+		err := fa.write(msg, chunk)
+		if err == nil {
+			return nil
+		}
+
+		if fa.conn != nil {
+			fa.conn.Close()
+			fa.conn = nil
+		}
+
+		if attempt >= fa.retries {
+			return err
+		}
+
+		time.Sleep(b.next())
+	}
+}
+
+// Making one attempt of sending message. Caller has to hold lock.
+func (fa *FluentAppender) write(msg []byte, chunk string) error {
+	if fa.conn == nil {
+		conn, err := fa.dial()
+		if err != nil {
+			return err
+		}
+
+		fa.conn = conn
+	}
+
+	if fa.timeout > 0 {
+		fa.conn.SetWriteDeadline(time.Now().Add(fa.timeout))
+	}
+
+	if _, err := fa.conn.Write(msg); err != nil {
+		return err
+	}
+
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	fa.conn.SetReadDeadline(time.Now().Add(fa.ackTimeout))
+	resp, err := newMsgpackDecoder(fa.conn).decode()
+	if err != nil {
+		return err
+	}
+
+	if m, ok := resp.(map[string]interface{}); !ok || m["ack"] != chunk {
+		return errFluentAck
+	}
+
+	return nil
+}
+
+func (fa *FluentAppender) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: fa.timeout}
+	if fa.tls != nil {
+		return tls.DialWithDialer(dialer, fa.network, fa.address, fa.tls)
+	}
+
+	return dialer.Dial(fa.network, fa.address)
+}
+
+// Will encode message in Forward or PackedForward mode.
+// Message has option with number of entries, and chunk id if ack is enabled.
+func (fa *FluentAppender) message(tag string, logs []golog.Log, chunk string) []byte {
+	entries := &msgpackEncoder{}
+	if fa.mode == FluentForward {
+		entries.writeArrayHeader(len(logs))
+	}
+
+	for _, log := range logs {
+		entries.writeArrayHeader(2)
+
+		timestamp := log.Time
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		if fa.eventTime {
+			entries.writeEventTime(timestamp)
+		} else {
+			entries.writeInt(timestamp.Unix())
+		}
+
+		entries.writeMap(fa.Record(log))
+	}
+
+	option := map[string]interface{}{"size": len(logs)}
+	if len(chunk) > 0 {
+		option["chunk"] = chunk
+	}
+
+	e := &msgpackEncoder{}
+	e.writeArrayHeader(3)
+	e.writeString(tag)
+
+	if fa.mode == FluentForward {
+		e.buf = append(e.buf, entries.bytes()...)
+	} else {
+		e.writeBinary(entries.bytes())
+	}
+
+	e.writeMap(option)
+
+	return e.bytes()
+}
+
+// Will convert log to record which is sent to Fluentd.
+func (fa *FluentAppender) Record(log golog.Log) map[string]interface{} {
+	record := map[string]interface{}{
+		"message": log.Message,
+		"level":   log.Level.Name,
+		"pid":     log.Pid,
+	}
+
+	if name := loggerName(log); len(name) > 0 {
+		record["logger"] = name
+	}
+
+	if len(log.Ctx) > 0 {
+		record["ctx"] = log.Ctx
+	}
+
+	if len(log.Data) > 0 {
+		record["data"] = log.Data
+	}
+
+	return record
+}
+
+// Will set function which is called when batch of logs cannot be sent.
+// By default errors are printed.
+func (fa *FluentAppender) OnError(fn func(err error, logs []golog.Log)) *FluentAppender {
+	fa.batcher.setErrorHandler(fn)
+	return fa
+}
+
+func (fa *FluentAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	fa.batcher.watchers.add(fn)
+}
+
+// Will send current batch, and wait until all pending batches are sent.
+func (fa *FluentAppender) Flush() error {
+	return fa.batcher.flush()
+}
+
+// Will send all collected logs, stop appender and close connection.
+func (fa *FluentAppender) Close() error {
+	err := fa.batcher.close()
+
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	if fa.conn != nil {
+		fa.conn.Close()
+		fa.conn = nil
+	}
+
+	return err
+}
+
+// Function for creating Fluentd appender.
+// Connection is made when first batch is sent, so this function never blocks.
+// Supported configuration keys are:
+// network - tcp or unix (default tcp)
+// address - address of Fluentd, or path of unix socket (default 127.0.0.1:24224)
+// tag_prefix - prefix of tags, tag is prefix followed by logger name (default golog)
+// mode - forward or packed (default packed)
+// ack - wait for server to confirm every message (default false)
+// ack_timeout - max time of waiting for confirmation (default 10s)
+// event_time - send time with nanoseconds, instead of seconds (default true)
+// retries - number of retries of failed message (default 3)
+// backoff - first delay between retries (default 500ms)
+// max_backoff - max delay between retries (default 30s)
+// timeout - timeout for connecting and writing (default 5s)
+// tls, tls_ca, tls_server_name, tls_skip_verify - TLS configuration, the same as in network appender
+// Batching is configured using the same keys as in HTTP appender.
+func Fluent(cnf golog.Conf) *FluentAppender {
+	config, err := tlsConf(cnf)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+
+	mode := confString(cnf, "mode", FluentPackedForward)
+	if mode != FluentForward && mode != FluentPackedForward {
+		fmt.Println("unknown fluent mode " + mode + ", using packed")
+		mode = FluentPackedForward
+	}
+
+	tagPrefix, ok := cnf["tag_prefix"]
+	if !ok {
+		tagPrefix = "golog"
+	}
+
+	fa := &FluentAppender{
+		network:    confString(cnf, "network", "tcp"),
+		address:    confString(cnf, "address", "127.0.0.1:24224"),
+		tls:        config,
+		timeout:    confDuration(cnf, "timeout", 5*time.Second),
+		ackTimeout: confDuration(cnf, "ack_timeout", 10*time.Second),
+		tagPrefix:  tagPrefix,
+		mode:       mode,
+		ack:        confBool(cnf, "ack", false),
+		eventTime:  confBool(cnf, "event_time", true),
+		retries:    confInt(cnf, "retries", 3),
+		backoff: backoff{
+			min: confDuration(cnf, "backoff", 500*time.Millisecond),
+			max: confDuration(cnf, "max_backoff", 30*time.Second),
+		},
+	}
+
+	fa.batcher = newBatcher(cnf, fa.AppendBatch)
+
+	return fa
+}
@@ -0,0 +1,275 @@
+package appenders
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Entry received by forward server
+type fluentEntry struct {
+	tag    string
+	time   interface{}
+	record map[string]interface{}
+}
+
+// Small forward protocol server. It decodes messages in Forward and PackedForward modes,
+// and sends ack if client asks for it. If skipAcks is set, first messages are not confirmed,
+// and messages with skipTag are never confirmed.
+type fluentServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	entries  []fluentEntry
+	messages int
+	skipAcks int
+	skipTag  string
+}
+
+func newFluentServer(t *testing.T) *fluentServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	s := &fluentServer{ln: ln}
+	go s.serve()
+	return s
+}
+
+func (s *fluentServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *fluentServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	d := newMsgpackDecoder(conn)
+	for {
+		msg, err := d.decode()
+		if err != nil {
+			return
+		}
+
+		parts := msg.([]interface{})
+		tag := parts[0].(string)
+
+		var entries []interface{}
+		switch v := parts[1].(type) {
+		case []interface{}:
+			entries = v
+		case []byte:
+			packed := newMsgpackDecoder(bytes.NewReader(v))
+			for {
+				entry, err := packed.decode()
+				if err != nil {
+					break
+				}
+
+				entries = append(entries, entry)
+			}
+		}
+
+		option := parts[2].(map[string]interface{})
+
+		s.mu.Lock()
+		s.messages++
+		skip := s.skipAcks > 0 || tag == s.skipTag
+		if skip {
+			if s.skipAcks > 0 {
+				s.skipAcks--
+			}
+		} else {
+			for _, entry := range entries {
+				e := entry.([]interface{})
+				s.entries = append(s.entries, fluentEntry{tag, e[0], e[1].(map[string]interface{})})
+			}
+		}
+		s.mu.Unlock()
+
+		if chunk, ok := option["chunk"]; ok && !skip {
+			e := &msgpackEncoder{}
+			e.writeMap(map[string]interface{}{"ack": chunk})
+			conn.Write(e.bytes())
+		}
+	}
+}
+
+func (s *fluentServer) received() ([]fluentEntry, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.entries, s.messages
+}
+
+func TestFluentId(t *testing.T) {
+	appender := Fluent(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/fluent", appender.Id())
+}
+
+func TestFluentTag(t *testing.T) {
+	appender := Fluent(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "golog.github.com.someone.project", appender.Tag(golog.Log{Logger: &golog.Logger{Name: "github.com/someone/project"}}))
+	assert.Equal(t, "golog.some_logger", appender.Tag(golog.Log{Logger: &golog.Logger{Name: "some logger"}}))
+	assert.Equal(t, "golog", appender.Tag(golog.Log{}))
+
+	appender = Fluent(golog.Conf{"tag_prefix": ""})
+	defer appender.Close()
+
+	assert.Equal(t, "somelogger", appender.Tag(golog.Log{Logger: &golog.Logger{Name: "somelogger"}}))
+	assert.Equal(t, "golog", appender.Tag(golog.Log{}))
+}
+
+func TestFluentModes(t *testing.T) {
+	for _, mode := range []string{FluentForward, FluentPackedForward} {
+		srv := newFluentServer(t)
+
+		appender := Fluent(golog.Conf{
+			"address":        srv.ln.Addr().String(),
+			"mode":           mode,
+			"flush_interval": "1h",
+		})
+
+		now := time.Unix(1700000000, 123)
+		first := &golog.Logger{Name: "first"}
+		second := &golog.Logger{Name: "second"}
+
+		appender.Append(golog.Log{Message: "message 1", Level: golog.INFO, Time: now, Logger: first, Ctx: golog.Ctx{"key": "value"}})
+		appender.Append(golog.Log{Message: "message 2", Time: now, Logger: second})
+		appender.Append(golog.Log{Message: "message 3", Time: now, Logger: first})
+		assert.Nil(t, appender.Close())
+
+		time.Sleep(50 * time.Millisecond)
+		entries, messages := srv.received()
+
+		// one message is sent for every tag
+		assert.Exactly(t, 2, messages)
+		assert.Len(t, entries, 3)
+
+		assert.Equal(t, "golog.first", entries[0].tag)
+		assert.Equal(t, "message 1", entries[0].record["message"])
+		assert.Equal(t, "INFO", entries[0].record["level"])
+		assert.Equal(t, "first", entries[0].record["logger"])
+		assert.Equal(t, map[string]interface{}{"key": "value"}, entries[0].record["ctx"])
+		assert.Equal(t, msgpackExt{0, []byte{0x65, 0x53, 0xf1, 0x00, 0, 0, 0, 123}}, entries[0].time)
+
+		assert.Equal(t, "message 3", entries[1].record["message"])
+		assert.Equal(t, "golog.second", entries[2].tag)
+
+		srv.ln.Close()
+	}
+}
+
+func TestFluentAck(t *testing.T) {
+	srv := newFluentServer(t)
+	defer srv.ln.Close()
+
+	// first message is not confirmed, so it is sent again
+	srv.skipAcks = 1
+
+	appender := Fluent(golog.Conf{
+		"address":     srv.ln.Addr().String(),
+		"ack":         "true",
+		"ack_timeout": "50ms",
+		"backoff":     "1ms",
+		"event_time":  "false",
+	})
+
+	appender.Append(golog.Log{Message: "some message", Time: time.Unix(1700000000, 0)})
+	assert.Nil(t, appender.Flush())
+
+	entries, messages := srv.received()
+	assert.Exactly(t, 2, messages)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, int64(1700000000), entries[0].time)
+
+	assert.Nil(t, appender.Close())
+}
+
+func TestFluentPartialFailure(t *testing.T) {
+	srv := newFluentServer(t)
+	defer srv.ln.Close()
+
+	appender := Fluent(golog.Conf{
+		"address":        srv.ln.Addr().String(),
+		"ack":            "true",
+		"ack_timeout":    "50ms",
+		"retries":        "0",
+		"flush_interval": "1h",
+	})
+
+	srv.mu.Lock()
+	srv.skipTag = appender.Tag(golog.Log{Logger: &golog.Logger{Name: "b"}})
+	srv.mu.Unlock()
+
+	var failed []string
+	appender.OnError(func(err error, logs []golog.Log) {
+		for _, log := range logs {
+			failed = append(failed, log.Message)
+		}
+	})
+
+	appender.Append(golog.Log{Message: "first", Logger: &golog.Logger{Name: "a"}})
+	appender.Append(golog.Log{Message: "second", Logger: &golog.Logger{Name: "b"}})
+	appender.Append(golog.Log{Message: "third", Logger: &golog.Logger{Name: "c"}})
+	appender.Append(golog.Log{Message: "fourth", Logger: &golog.Logger{Name: "a"}})
+	assert.Nil(t, appender.Close())
+
+	// logs of first tag are delivered, so they are not reported
+	assert.Equal(t, []string{"second", "third"}, failed)
+	entries, _ := srv.received()
+	assert.Len(t, entries, 2)
+}
+
+func TestFluentReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	appender := Fluent(golog.Conf{
+		"address": address,
+		"backoff": "1ms",
+		"retries": "1",
+	})
+
+	var failed []golog.Log
+	appender.OnError(func(err error, logs []golog.Log) {
+		failed = append(failed, logs...)
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Flush()
+	assert.Len(t, failed, 1)
+
+	ln, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skip("cannot listen on the same address again")
+	}
+
+	srv := &fluentServer{ln: ln}
+	go srv.serve()
+	defer ln.Close()
+
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Close())
+
+	time.Sleep(50 * time.Millisecond)
+	entries, _ := srv.received()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "second", entries[0].record["message"])
+}
@@ -0,0 +1,348 @@
+package appenders
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+const (
+	// max number of chunks of one GELF message
+	gelfMaxChunks = 128
+
+	// size of chunk header: magic bytes, message id, sequence number and count
+	gelfChunkHeader = 12
+)
+
+var (
+	gelfMagic = []byte{0x1e, 0x0f}
+
+	// allowed names of GELF additional fields
+	gelfFieldName = regexp.MustCompile(`[^\w\.\-]`)
+
+	errGelfTooBig       = errors.New("gelf: message is too big to be sent in 128 chunks")
+	errGelfNotConnected = errors.New("gelf: not connected, waiting before next reconnect")
+)
+
+// Representing appender which sends logs to Graylog using GELF 1.1 format.
+// Over udp messages can be compressed and are split into chunks if needed.
+// Over tcp messages are not compressed and are terminated with null byte.
+type GelfAppender struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	backoff backoff
+	retryAt time.Time
+
+	network     string
+	address     string
+	host        string
+	compression string
+	chunkSize   int
+	timeout     time.Duration
+}
+
+// github.com/ivpusic/golog/appenders/gelf
+func (ga *GelfAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/gelf"
+}
+
+func (ga *GelfAppender) Append(log golog.Log) {
+	if err := ga.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Sending log to Graylog, and returning error if log cannot be sent.
+// If tcp connection is broken, appender will reconnect and try again once.
+func (ga *GelfAppender) TryAppend(log golog.Log) error {
+	msg, err := json.Marshal(ga.Message(log))
+	if err != nil {
+		return err
+	}
+
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	if strings.HasPrefix(ga.network, "tcp") {
+		connected := ga.conn != nil
+		err = ga.writeTCP(msg)
+		if err != nil && connected {
+			err = ga.writeTCP(msg)
+		}
+
+		return err
+	}
+
+	return ga.writeUDP(msg)
+}
+
+func (ga *GelfAppender) writeTCP(msg []byte) error {
+	if err := ga.connect(); err != nil {
+		return err
+	}
+
+	return ga.write(append(msg, 0))
+}
+
+func (ga *GelfAppender) writeUDP(msg []byte) error {
+	msg, err := ga.compress(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := ga.connect(); err != nil {
+		return err
+	}
+
+	if len(msg) <= ga.chunkSize {
+		return ga.write(msg)
+	}
+
+	chunks, err := ga.chunks(msg)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		if err := ga.write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Will split message into chunks. Every chunk starts with header
+// which contains magic bytes, message id, sequence number and sequence count.
+func (ga *GelfAppender) chunks(msg []byte) ([][]byte, error) {
+	size := ga.chunkSize - gelfChunkHeader
+	count := (len(msg) + size - 1) / size
+	if count > gelfMaxChunks {
+		return nil, errGelfTooBig
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(msg) {
+			end = len(msg)
+		}
+
+		chunk := make([]byte, 0, gelfChunkHeader+end-i*size)
+		chunk = append(chunk, gelfMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*size:end]...)
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+func (ga *GelfAppender) compress(msg []byte) ([]byte, error) {
+	var w io.WriteCloser
+	buf := &bytes.Buffer{}
+
+	switch ga.compression {
+	case "gzip":
+		w = gzip.NewWriter(buf)
+	case "zlib":
+		w = zlib.NewWriter(buf)
+	default:
+		return msg, nil
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Will connect to Graylog if appender is not connected.
+// After failed attempt, next attempt is made after exponential backoff.
+func (ga *GelfAppender) connect() error {
+	if ga.conn != nil {
+		return nil
+	}
+
+	if time.Now().Before(ga.retryAt) {
+		return errGelfNotConnected
+	}
+
+	conn, err := net.DialTimeout(ga.network, ga.address, ga.timeout)
+	if err != nil {
+		ga.retryAt = time.Now().Add(ga.backoff.next())
+		return err
+	}
+
+	ga.backoff.reset()
+	ga.conn = conn
+	return nil
+}
+
+func (ga *GelfAppender) write(msg []byte) error {
+	if ga.timeout > 0 {
+		ga.conn.SetWriteDeadline(time.Now().Add(ga.timeout))
+	}
+
+	_, err := ga.conn.Write(msg)
+	if err != nil {
+		ga.conn.Close()
+		ga.conn = nil
+	}
+
+	return err
+}
+
+// Will close connection to Graylog.
+// Appender will connect again on next log.
+func (ga *GelfAppender) Close() error {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	if ga.conn == nil {
+		return nil
+	}
+
+	err := ga.conn.Close()
+	ga.conn = nil
+	return err
+}
+
+// Will convert log to GELF message.
+// Logger name, process id, context and data are sent as additional fields.
+func (ga *GelfAppender) Message(log golog.Log) map[string]interface{} {
+	short := log.Message
+	if i := strings.IndexByte(short, '\n'); i >= 0 {
+		short = short[:i]
+	}
+
+	if len(short) == 0 {
+		// short message is required
+		short = "-"
+	}
+
+	timestamp := log.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	msg := map[string]interface{}{
+		"version":       "1.1",
+		"host":          ga.host,
+		"short_message": short,
+		"timestamp":     float64(timestamp.UnixMilli()) / 1000,
+		"level":         syslogSeverity(log.Level),
+		"_pid":          log.Pid,
+		"_level_name":   log.Level.Name,
+	}
+
+	if short != log.Message {
+		msg["full_message"] = log.Message
+	}
+
+	if name := loggerName(log); len(name) > 0 {
+		msg["_logger"] = name
+	}
+
+	if len(log.Data) > 0 {
+		msg["_data"] = gelfValue(log.Data)
+	}
+
+	// context is sent as additional fields, so it cannot replace standard fields,
+	// and it cannot replace fields which are set by appender
+	for k, v := range log.Ctx {
+		if field := gelfField(k); msg[field] == nil {
+			msg[field] = gelfValue(v)
+		}
+	}
+
+	return msg
+}
+
+// Will make valid name of additional field.
+// Field _id is reserved, so it is renamed to __id.
+func gelfField(name string) string {
+	name = "_" + gelfFieldName.ReplaceAllString(name, "_")
+	if name == "_id" {
+		name = "__id"
+	}
+
+	return name
+}
+
+// Additional fields can be only strings and numbers,
+// other values are sent as JSON strings.
+func gelfValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case bool:
+		return fmt.Sprint(v)
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
+
+// Function for creating GELF appender.
+// Connection is made on first log, so this function never fails.
+// Supported configuration keys are:
+// network - udp, tcp, tcp4 or tcp6 (default udp)
+// address - address of Graylog input (default 127.0.0.1:12201)
+// host - host name sent in messages (default host name of machine)
+// compression - gzip, zlib or none, used only with udp (default gzip)
+// chunk_size - max size of udp packet (default 1420)
+// timeout - timeout for connecting and writing (default 5s)
+func Gelf(cnf golog.Conf) *GelfAppender {
+	hostname, _ := os.Hostname()
+
+	chunkSize := confInt(cnf, "chunk_size", 1420)
+	if chunkSize <= gelfChunkHeader {
+		chunkSize = 1420
+	}
+
+	return &GelfAppender{
+		network:     confString(cnf, "network", "udp"),
+		address:     confString(cnf, "address", "127.0.0.1:12201"),
+		host:        confString(cnf, "host", hostname),
+		compression: confString(cnf, "compression", "gzip"),
+		chunkSize:   chunkSize,
+		timeout:     confDuration(cnf, "timeout", 5*time.Second),
+		backoff: backoff{
+			min: 100 * time.Millisecond,
+			max: 30 * time.Second,
+		},
+	}
+}
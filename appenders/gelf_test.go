@@ -0,0 +1,228 @@
+package appenders
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Will read one GELF message from udp connection, reassembling chunks if needed.
+func readGelfUDP(t *testing.T, conn net.PacketConn) []byte {
+	buf := make([]byte, 65536)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var parts [][]byte
+	received := 0
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		packet := append([]byte{}, buf[:n]...)
+		if !bytes.HasPrefix(packet, gelfMagic) {
+			return packet
+		}
+
+		seq, count := int(packet[10]), int(packet[11])
+		if parts == nil {
+			parts = make([][]byte, count)
+		}
+
+		parts[seq] = packet[gelfChunkHeader:]
+		received++
+
+		if received == count {
+			return bytes.Join(parts, nil)
+		}
+	}
+}
+
+func decompressGelf(t *testing.T, msg []byte) map[string]interface{} {
+	var r io.Reader = bytes.NewReader(msg)
+
+	switch {
+	case bytes.HasPrefix(msg, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(r)
+		assert.Nil(t, err)
+		r = gr
+	case msg[0] == 0x78:
+		zr, err := zlib.NewReader(r)
+		assert.Nil(t, err)
+		r = zr
+	}
+
+	decoded := map[string]interface{}{}
+	assert.Nil(t, json.NewDecoder(r).Decode(&decoded))
+	return decoded
+}
+
+func TestGelfId(t *testing.T) {
+	appender := Gelf(golog.Conf{})
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/gelf", appender.Id())
+}
+
+func TestGelfMessage(t *testing.T) {
+	appender := Gelf(golog.Conf{"host": "somehost"})
+
+	msg := appender.Message(golog.Log{
+		Message: "first line\nsecond line",
+		Level:   golog.WARN,
+		Time:    time.Unix(1500000000, 123000000),
+		Logger:  &golog.Logger{Name: "github.com/someone/project"},
+		Pid:     42,
+		Ctx:     golog.Ctx{"id": "someid", "user name": "john", "ok": true},
+		Data:    []interface{}{1, "two"},
+	})
+
+	assert.Equal(t, "1.1", msg["version"])
+	assert.Equal(t, "somehost", msg["host"])
+	assert.Equal(t, "first line", msg["short_message"])
+	assert.Equal(t, "first line\nsecond line", msg["full_message"])
+	assert.Equal(t, 1500000000.123, msg["timestamp"])
+	assert.Exactly(t, 4, msg["level"])
+	assert.Equal(t, "WARN", msg["_level_name"])
+	assert.Equal(t, "github.com/someone/project", msg["_logger"])
+	assert.Equal(t, "someid", msg["__id"])
+	assert.Equal(t, "john", msg["_user_name"])
+	assert.Equal(t, "true", msg["_ok"])
+	assert.Equal(t, `[1,"two"]`, msg["_data"])
+
+	// context doesn't replace standard fields and fields set by appender
+	msg = appender.Message(golog.Log{
+		Message: "some message",
+		Pid:     42,
+		Ctx:     golog.Ctx{"version": "2", "host": "otherhost", "level": 1, "pid": 1, "level_name": "DEBUG"},
+	})
+
+	assert.Equal(t, "1.1", msg["version"])
+	assert.Equal(t, "somehost", msg["host"])
+	assert.Equal(t, "2", msg["_version"])
+	assert.Equal(t, "otherhost", msg["_host"])
+	assert.Equal(t, 1, msg["_level"])
+	assert.Equal(t, 42, msg["_pid"])
+	assert.Equal(t, "", msg["_level_name"])
+
+	msg = appender.Message(golog.Log{Message: "single line"})
+	assert.Equal(t, "single line", msg["short_message"])
+	assert.Nil(t, msg["full_message"])
+}
+
+func TestGelfUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	for _, compression := range []string{"gzip", "zlib", "none"} {
+		appender := Gelf(golog.Conf{
+			"address":     conn.LocalAddr().String(),
+			"compression": compression,
+		})
+
+		assert.Nil(t, appender.TryAppend(golog.Log{Message: "some message", Level: golog.ERROR}))
+
+		msg := decompressGelf(t, readGelfUDP(t, conn))
+		assert.Equal(t, "some message", msg["short_message"])
+		assert.Equal(t, float64(3), msg["level"])
+
+		appender.Close()
+	}
+}
+
+func TestGelfChunks(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	appender := Gelf(golog.Conf{
+		"address":     conn.LocalAddr().String(),
+		"compression": "none",
+		"chunk_size":  "100",
+	})
+	defer appender.Close()
+
+	big := strings.Repeat("a", 1000)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: big}))
+
+	msg := decompressGelf(t, readGelfUDP(t, conn))
+	assert.Equal(t, big, msg["short_message"])
+
+	// message which needs more than 128 chunks is not sent
+	err = appender.TryAppend(golog.Log{Message: strings.Repeat("a", 128*100)})
+	assert.Equal(t, errGelfTooBig, err)
+}
+
+func TestGelfTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			msg, err := r.ReadBytes(0)
+			if err != nil {
+				return
+			}
+
+			received <- msg
+		}
+	}()
+
+	// tcp4 and tcp6 are stream connections too
+	appender := Gelf(golog.Conf{
+		"network": "tcp4",
+		"address": ln.Addr().String(),
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first message"})
+	appender.Append(golog.Log{Message: "second message", Ctx: golog.Ctx{"err": errors.New("some error")}})
+
+	for _, expected := range []string{"first message", "second message"} {
+		select {
+		case msg := <-received:
+			assert.Equal(t, byte(0), msg[len(msg)-1])
+
+			decoded := map[string]interface{}{}
+			assert.Nil(t, json.Unmarshal(msg[:len(msg)-1], &decoded))
+			assert.Equal(t, expected, decoded["short_message"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("message is not received")
+		}
+	}
+}
+
+func TestGelfNotConnected(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	appender := Gelf(golog.Conf{
+		"network": "tcp",
+		"address": address,
+	})
+
+	// after failed attempt, appender waits before connecting again
+	assert.NotNil(t, appender.TryAppend(golog.Log{Message: "first message"}))
+	assert.Equal(t, errGelfNotConnected, appender.TryAppend(golog.Log{Message: "second message"}))
+}
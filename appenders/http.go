@@ -0,0 +1,272 @@
+package appenders
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Sending HTTP requests for appenders, with retries and exponential backoff.
+// Requests are retried on network errors, and on 429 and 5xx responses.
+type httpSender struct {
+	client  *http.Client
+	headers http.Header
+	gzip    bool
+	retries int
+	backoff backoff
+}
+
+// Will make sender from appender configuration.
+// Default of gzip key differs between appenders, so it is provided by caller.
+// Headers can be configured using keys with header. prefix,
+// for example "header.X-Api-Key": "somekey".
+func newHTTPSender(cnf golog.Conf, gzipDefault bool) *httpSender {
+	headers := http.Header{}
+	for k, v := range cnf {
+		if strings.HasPrefix(k, "header.") {
+			headers.Set(k[len("header."):], v)
+		}
+	}
+
+	if token := cnf["token"]; len(token) > 0 {
+		headers.Set("Authorization", "Bearer "+token)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	config, err := tlsConf(cnf)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+
+	if config != nil {
+		transport.TLSClientConfig = config
+	}
+
+	return &httpSender{
+		client: &http.Client{
+			Timeout:   confDuration(cnf, "timeout", 10*time.Second),
+			Transport: transport,
+		},
+		headers: headers,
+		gzip:    confBool(cnf, "gzip", gzipDefault),
+		retries: confInt(cnf, "retries", 3),
+		backoff: backoff{
+			min: confDuration(cnf, "backoff", 500*time.Millisecond),
+			max: confDuration(cnf, "max_backoff", 30*time.Second),
+		},
+	}
+}
+
+// Will send request and return body of successful response.
+// If response has Retry-After header, next attempt is not made before requested time.
+// If requested time is longer than max backoff, request fails without waiting.
+func (s *httpSender) send(method, url, contentType string, body []byte) ([]byte, error) {
+	var err error
+	if s.gzip {
+		if body, err = gzipBody(body); err != nil {
+			return nil, err
+		}
+	}
+
+	b := s.backoff
+	for attempt := 0; ; attempt++ {
+		var respBody []byte
+		var wait time.Duration
+		var retry bool
+
+		respBody, wait, retry, err = s.do(method, url, contentType, body)
+		if err == nil {
+			return respBody, nil
+		}
+
+		if !retry || attempt >= s.retries {
+			return nil, err
+		}
+
+		// closing appender waits for pending batches,
+		// so it should not hang for too long
+		if wait > s.backoff.max {
+			return nil, errors.New("http: server asked to retry after " + wait.String() + ", which is longer than max_backoff")
+		}
+
+		if delay := b.next(); delay > wait {
+			wait = delay
+		}
+
+		time.Sleep(wait)
+	}
+}
+
+// Making one attempt. Returns response body, requested delay before next attempt,
+// and whether request should be retried.
+func (s *httpSender) do(method, url, contentType string, body []byte) ([]byte, time.Duration, bool, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	for k, v := range s.headers {
+		req.Header[k] = v
+	}
+
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if s.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, 0, true, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, 0, true, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return respBody, 0, false, nil
+	}
+
+	err = fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(respBody)))
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return nil, retryAfter(resp.Header.Get("Retry-After")), retry, err
+}
+
+// Parsing Retry-After header, which can contain number of seconds or HTTP date.
+func retryAfter(value string) time.Duration {
+	if len(value) == 0 {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Representing appender which sends logs to HTTP endpoint as newline delimited JSON.
+// Logs are collected in batches, and sent from background goroutine.
+type HTTPAppender struct {
+	url     string
+	encode  func(golog.Log) ([]byte, error)
+	sender  *httpSender
+	batcher *batcher
+}
+
+// github.com/ivpusic/golog/appenders/http
+func (ha *HTTPAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/http"
+}
+
+func (ha *HTTPAppender) Append(log golog.Log) {
+	if err := ha.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+func (ha *HTTPAppender) TryAppend(log golog.Log) error {
+	return ha.batcher.add(log)
+}
+
+// Sending logs in one request, with retries.
+func (ha *HTTPAppender) AppendBatch(logs []golog.Log) error {
+	body := &bytes.Buffer{}
+	for _, log := range logs {
+		line, err := ha.encode(log)
+		if err != nil {
+			return err
+		}
+
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+
+	_, err := ha.sender.send("POST", ha.url, "application/x-ndjson", body.Bytes())
+	return err
+}
+
+// Will set function which is called when batch of logs cannot be sent.
+// By default errors are printed.
+func (ha *HTTPAppender) OnError(fn func(err error, logs []golog.Log)) *HTTPAppender {
+	ha.batcher.setErrorHandler(fn)
+	return ha
+}
+
+func (ha *HTTPAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	ha.batcher.watchers.add(fn)
+}
+
+// Will send current batch, and wait until all pending batches are sent.
+func (ha *HTTPAppender) Flush() error {
+	return ha.batcher.flush()
+}
+
+// Will send all collected logs and stop appender.
+func (ha *HTTPAppender) Close() error {
+	return ha.batcher.close()
+}
+
+// Function for creating HTTP appender.
+// Supported configuration keys are:
+// url - endpoint which accepts newline delimited JSON
+// encoding - json or text (default json)
+// token - token sent in Authorization header as bearer token
+// header.<name> - additional request header, for example "header.X-Api-Key"
+// gzip - compress request body (default true)
+// batch_size - max number of logs in batch (default 100)
+// batch_bytes - max approximate size of batch in bytes (default 1MB)
+// flush_interval - max time between batches (default 1s)
+// max_pending - max number of batches waiting to be sent (default 10)
+// retries - number of retries of failed request (default 3)
+// backoff - first delay between retries (default 500ms)
+// max_backoff - max delay between retries (default 30s)
+// timeout - timeout of one request (default 10s)
+func HTTP(cnf golog.Conf) *HTTPAppender {
+	ha := &HTTPAppender{
+		url:    cnf["url"],
+		encode: encoding(cnf["encoding"]),
+		sender: newHTTPSender(cnf, true),
+	}
+
+	ha.batcher = newBatcher(cnf, ha.AppendBatch)
+
+	return ha
+}
@@ -0,0 +1,240 @@
+package appenders
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// HTTP server which remembers received logs
+type logServer struct {
+	mu       sync.Mutex
+	requests int
+	logs     []golog.Log
+	headers  []http.Header
+
+	// status codes returned for requests, after them 200 is returned
+	statuses []int
+}
+
+func (s *logServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	s.headers = append(s.headers, r.Header)
+
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(status)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		body, _ = gzip.NewReader(r.Body)
+	}
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		log := golog.Log{}
+		json.Unmarshal(scanner.Bytes(), &log)
+		s.logs = append(s.logs, log)
+	}
+}
+
+func (s *logServer) received() (int, []golog.Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests, s.logs
+}
+
+func TestHTTPId(t *testing.T) {
+	appender := HTTP(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/http", appender.Id())
+}
+
+func TestHTTPBatchSize(t *testing.T) {
+	ls := &logServer{}
+	srv := httptest.NewServer(ls)
+	defer srv.Close()
+
+	appender := HTTP(golog.Conf{
+		"url":            srv.URL,
+		"batch_size":     "2",
+		"flush_interval": "1h",
+		"token":          "sometoken",
+		"header.X-Test":  "value",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Append(golog.Log{Message: "second"})
+	appender.Append(golog.Log{Message: "third"})
+	appender.Flush()
+
+	requests, logs := ls.received()
+	assert.Exactly(t, 2, requests)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, "third", logs[2].Message)
+
+	assert.Equal(t, "Bearer sometoken", ls.headers[0].Get("Authorization"))
+	assert.Equal(t, "value", ls.headers[0].Get("X-Test"))
+	assert.Equal(t, "application/x-ndjson", ls.headers[0].Get("Content-Type"))
+
+	// body is compressed by default
+	assert.Equal(t, "gzip", ls.headers[0].Get("Content-Encoding"))
+}
+
+func TestHTTPFlushInterval(t *testing.T) {
+	ls := &logServer{}
+	srv := httptest.NewServer(ls)
+	defer srv.Close()
+
+	appender := HTTP(golog.Conf{
+		"url":            srv.URL,
+		"flush_interval": "10ms",
+		"gzip":           "false",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "some message"})
+
+	for i := 0; i < 100; i++ {
+		if _, logs := ls.received(); len(logs) == 1 {
+			assert.Equal(t, "", ls.headers[0].Get("Content-Encoding"))
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("log is not sent")
+}
+
+func TestHTTPRetry(t *testing.T) {
+	ls := &logServer{statuses: []int{503, 429}}
+	srv := httptest.NewServer(ls)
+	defer srv.Close()
+
+	appender := HTTP(golog.Conf{
+		"url":     srv.URL,
+		"backoff": "1ms",
+	})
+
+	appender.Append(golog.Log{Message: "some message"})
+	assert.Nil(t, appender.Close())
+
+	requests, logs := ls.received()
+	assert.Exactly(t, 3, requests)
+	assert.Len(t, logs, 1)
+}
+
+func TestHTTPError(t *testing.T) {
+	ls := &logServer{statuses: []int{400, 503, 503}}
+	srv := httptest.NewServer(ls)
+	defer srv.Close()
+
+	appender := HTTP(golog.Conf{
+		"url":     srv.URL,
+		"backoff": "1ms",
+		"retries": "1",
+	})
+
+	var failed []golog.Log
+	appender.OnError(func(err error, logs []golog.Log) {
+		assert.NotNil(t, err)
+		failed = append(failed, logs...)
+	})
+
+	// client errors are not retried
+	appender.Append(golog.Log{Message: "first"})
+	appender.Flush()
+
+	requests, _ := ls.received()
+	assert.Exactly(t, 1, requests)
+	assert.Len(t, failed, 1)
+
+	// server errors are retried
+	appender.Append(golog.Log{Message: "second"})
+	appender.Close()
+
+	requests, _ = ls.received()
+	assert.Exactly(t, 3, requests)
+	assert.Len(t, failed, 2)
+
+	assert.Equal(t, errBatchClosed, appender.TryAppend(golog.Log{Message: "third"}))
+}
+
+func TestHTTPMaxPending(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+
+	appender := HTTP(golog.Conf{
+		"url":            srv.URL,
+		"batch_size":     "1",
+		"max_pending":    "1",
+		"flush_interval": "1h",
+	})
+
+	dropped := 0
+	appender.OnError(func(err error, logs []golog.Log) {
+		if err == errBatchDropped {
+			dropped++
+		}
+	})
+
+	// first batch is being sent, second one is pending,
+	// and third one replaces second one
+	appender.TryAppend(golog.Log{Message: "first"})
+	time.Sleep(50 * time.Millisecond)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "second"}))
+	// third log is accepted, and dropped batch is only reported
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "third"}))
+	assert.Exactly(t, 1, dropped)
+
+	close(block)
+	appender.Close()
+}
+
+func TestHTTPRetryAfterTooLong(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(429)
+	}))
+	defer srv.Close()
+
+	// request fails instead of retrying earlier than server asked
+	sender := newHTTPSender(golog.Conf{"max_backoff": "1s"}, false)
+	_, err := sender.send("POST", srv.URL, "", []byte("body"))
+	assert.NotNil(t, err)
+	assert.Exactly(t, 1, requests)
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), retryAfter(""))
+	assert.Equal(t, time.Duration(0), retryAfter("invalid"))
+	assert.Equal(t, 2*time.Second, retryAfter("2"))
+
+	wait := retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, wait > 50*time.Second && wait <= time.Minute)
+}
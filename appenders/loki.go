@@ -0,0 +1,338 @@
+package appenders
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Label value used instead of values of high cardinality labels,
+// when cardinality configuration key is set to fold.
+const LokiOtherValue = "__other__"
+
+var lokiLabelName = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Stream of logs with the same labels, in format of Loki push API.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPush struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+// Representing appender which pushes logs to Grafana Loki.
+// Logs are grouped into streams by labels made from logger name, level
+// and selected context keys, and pushed in batches from background goroutine.
+//
+// To keep number of streams bounded, every context label can have only limited
+// number of different values. After limit is reached, new values are folded
+// into one value, or label is not used anymore, depending on configuration.
+// Context values are always part of log line, so nothing is lost.
+type LokiAppender struct {
+	url         string
+	encode      func(golog.Log) ([]byte, error)
+	sender      *httpSender
+	batcher     *batcher
+	static      map[string]string
+	loggerLabel string
+	levelLabel  string
+	ctxLabels   []string
+	maxValues   int
+	fold        bool
+
+	mu       sync.Mutex
+	values   map[string]map[string]struct{}
+	rejected map[string]bool
+}
+
+// github.com/ivpusic/golog/appenders/loki
+func (la *LokiAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/loki"
+}
+
+func (la *LokiAppender) Append(log golog.Log) {
+	if err := la.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+func (la *LokiAppender) TryAppend(log golog.Log) error {
+	return la.batcher.add(log)
+}
+
+// Pushing logs to Loki in one request, with retries.
+func (la *LokiAppender) AppendBatch(logs []golog.Log) error {
+	now := time.Now()
+	timestamps := make([]time.Time, len(logs))
+	order := make([]int, len(logs))
+	for i, log := range logs {
+		timestamps[i] = log.Time
+		if timestamps[i].IsZero() {
+			timestamps[i] = now
+		}
+
+		order[i] = i
+	}
+
+	// older Loki versions reject out of order entries
+	sort.SliceStable(order, func(i, j int) bool {
+		return timestamps[order[i]].Before(timestamps[order[j]])
+	})
+
+	streams := map[string]*lokiStream{}
+	push := lokiPush{}
+
+	for _, i := range order {
+		log := logs[i]
+
+		line, err := la.encode(log)
+		if err != nil {
+			return err
+		}
+
+		labels := la.Labels(log)
+		key := lokiStreamKey(labels)
+
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			push.Streams = append(push.Streams, stream)
+		}
+
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(timestamps[i].UnixNano(), 10),
+			string(line),
+		})
+	}
+
+	body, err := json.Marshal(push)
+	if err != nil {
+		return err
+	}
+
+	_, err = la.sender.send("POST", la.url, "application/json", body)
+	return err
+}
+
+// Will return labels of stream in which log is pushed.
+func (la *LokiAppender) Labels(log golog.Log) map[string]string {
+	labels := map[string]string{}
+	for k, v := range la.static {
+		labels[k] = v
+	}
+
+	if len(la.loggerLabel) > 0 {
+		if name := loggerName(log); len(name) > 0 {
+			labels[la.loggerLabel] = name
+		}
+	}
+
+	if len(la.levelLabel) > 0 && len(log.Level.Name) > 0 {
+		labels[la.levelLabel] = strings.ToLower(log.Level.Name)
+	}
+
+	for _, key := range la.ctxLabels {
+		value, ok := log.Ctx[key]
+		if !ok {
+			continue
+		}
+
+		if label, ok := la.labelValue(key, fmt.Sprint(value)); ok {
+			labels[lokiLabel(key)] = label
+		}
+	}
+
+	// Loki rejects streams without labels
+	if len(labels) == 0 {
+		labels["job"] = lokiDefaultJob()
+	}
+
+	return labels
+}
+
+// Will check cardinality of context label, and return value which should be used.
+// If label should not be used, false is returned.
+func (la *LokiAppender) labelValue(key, value string) (string, bool) {
+	if la.maxValues <= 0 {
+		return value, true
+	}
+
+	la.mu.Lock()
+	defer la.mu.Unlock()
+
+	if la.rejected[key] {
+		return "", false
+	}
+
+	values, ok := la.values[key]
+	if !ok {
+		values = map[string]struct{}{}
+		la.values[key] = values
+	}
+
+	if _, ok := values[value]; ok {
+		return value, true
+	}
+
+	if len(values) < la.maxValues {
+		values[value] = struct{}{}
+		return value, true
+	}
+
+	if la.fold {
+		return LokiOtherValue, true
+	}
+
+	fmt.Println("loki: label " + key + " has too many values, it will not be used anymore")
+	la.rejected[key] = true
+	return "", false
+}
+
+// Will set function which is called when batch of logs cannot be pushed.
+// By default errors are printed.
+func (la *LokiAppender) OnError(fn func(err error, logs []golog.Log)) *LokiAppender {
+	la.batcher.setErrorHandler(fn)
+	return la
+}
+
+func (la *LokiAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	la.batcher.watchers.add(fn)
+}
+
+// Will push current batch, and wait until all pending batches are pushed.
+func (la *LokiAppender) Flush() error {
+	return la.batcher.flush()
+}
+
+// Will push all collected logs and stop appender.
+func (la *LokiAppender) Close() error {
+	return la.batcher.close()
+}
+
+// Value of job label, which is used when stream would not have any label.
+func lokiDefaultJob() string {
+	return filepath.Base(os.Args[0])
+}
+
+// Will make valid Loki label name from context key.
+func lokiLabel(name string) string {
+	name = lokiLabelName.ReplaceAllString(name, "_")
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// Will make key which is the same for all logs with the same labels.
+func lokiStreamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, strconv.Quote(k)+"="+strconv.Quote(labels[k]))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Function for creating Loki appender.
+// Supported configuration keys are:
+// url - push endpoint (default http://127.0.0.1:3100/loki/api/v1/push)
+// tenant - tenant id sent in X-Scope-OrgID header
+// labels - comma separated context keys which are used as labels
+// label.<name> - static label, for example "label.job": "myapp"
+// logger_label - name of label with logger name, empty to disable (default logger)
+// level_label - name of label with level, empty to disable (default level)
+// max_label_values - max number of different values of context label, 0 for no limit (default 100)
+// cardinality - fold or drop, what to do with label which has too many values (default fold)
+// encoding - json or text, format of log line (default json)
+// Batching, retries, headers, gzip and timeout are configured
+// using the same keys as in HTTP appender, but gzip is false by default.
+func Loki(cnf golog.Conf) *LokiAppender {
+	static := map[string]string{}
+	for k, v := range cnf {
+		if strings.HasPrefix(k, "label.") {
+			static[lokiLabel(k[len("label."):])] = v
+		}
+	}
+
+	ctxLabels := []string{}
+	for _, key := range strings.Split(cnf["labels"], ",") {
+		if key = strings.TrimSpace(key); len(key) > 0 {
+			ctxLabels = append(ctxLabels, key)
+		}
+	}
+
+	// labels with logger name and level can be disabled using empty name
+	loggerLabel, ok := cnf["logger_label"]
+	if !ok {
+		loggerLabel = "logger"
+	}
+
+	if len(loggerLabel) > 0 {
+		loggerLabel = lokiLabel(loggerLabel)
+	}
+
+	levelLabel, ok := cnf["level_label"]
+	if !ok {
+		levelLabel = "level"
+	}
+
+	if len(levelLabel) > 0 {
+		levelLabel = lokiLabel(levelLabel)
+	}
+
+	// logs without context labels would be pushed in stream without labels
+	if len(loggerLabel) == 0 && len(levelLabel) == 0 && len(static) == 0 {
+		fmt.Println("loki: logger and level labels are disabled, and there are no static labels, using job label")
+		static["job"] = lokiDefaultJob()
+	}
+
+	cardinality := confString(cnf, "cardinality", "fold")
+	if cardinality != "fold" && cardinality != "drop" {
+		fmt.Println("unknown cardinality " + cardinality + ", using fold")
+		cardinality = "fold"
+	}
+
+	sender := newHTTPSender(cnf, false)
+	if tenant := cnf["tenant"]; len(tenant) > 0 {
+		sender.headers.Set("X-Scope-OrgID", tenant)
+	}
+
+	la := &LokiAppender{
+		url:         confString(cnf, "url", "http://127.0.0.1:3100/loki/api/v1/push"),
+		encode:      encoding(cnf["encoding"]),
+		sender:      sender,
+		static:      static,
+		loggerLabel: loggerLabel,
+		levelLabel:  levelLabel,
+		ctxLabels:   ctxLabels,
+		maxValues:   confInt(cnf, "max_label_values", 100),
+		fold:        cardinality == "fold",
+		values:      map[string]map[string]struct{}{},
+		rejected:    map[string]bool{},
+	}
+
+	la.batcher = newBatcher(cnf, la.AppendBatch)
+
+	return la
+}
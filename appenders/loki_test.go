@@ -0,0 +1,213 @@
+package appenders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Loki server which remembers received pushes
+type lokiServer struct {
+	mu      sync.Mutex
+	pushes  []lokiPush
+	headers []http.Header
+
+	// status codes returned for requests, after them 204 is returned
+	statuses []int
+}
+
+func (s *lokiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.headers = append(s.headers, r.Header)
+
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	push := lokiPush{}
+	json.NewDecoder(r.Body).Decode(&push)
+	s.pushes = append(s.pushes, push)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *lokiServer) streams() []*lokiStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	streams := []*lokiStream{}
+	for _, push := range s.pushes {
+		streams = append(streams, push.Streams...)
+	}
+
+	return streams
+}
+
+func TestLokiId(t *testing.T) {
+	appender := Loki(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/loki", appender.Id())
+}
+
+func TestLokiStreams(t *testing.T) {
+	ls := &lokiServer{}
+	srv := httptest.NewServer(ls)
+	defer srv.Close()
+
+	appender := Loki(golog.Conf{
+		"url":            srv.URL,
+		"labels":         "service, request-id",
+		"label.job":      "myapp",
+		"tenant":         "sometenant",
+		"flush_interval": "1h",
+	})
+	defer appender.Close()
+
+	logger := &golog.Logger{Name: "github.com/someone/project"}
+	now := time.Now()
+
+	appender.Append(golog.Log{Message: "second", Level: golog.INFO, Logger: logger, Time: now.Add(time.Second), Ctx: golog.Ctx{"service": "api"}})
+	appender.Append(golog.Log{Message: "first", Level: golog.INFO, Logger: logger, Time: now, Ctx: golog.Ctx{"service": "api"}})
+	appender.Append(golog.Log{Message: "third", Level: golog.ERROR, Logger: logger, Time: now, Ctx: golog.Ctx{"service": "api", "request-id": 5}})
+	assert.Nil(t, appender.Flush())
+
+	streams := ls.streams()
+	assert.Len(t, streams, 2)
+	assert.Equal(t, "sometenant", ls.headers[0].Get("X-Scope-OrgID"))
+
+	assert.Equal(t, map[string]string{
+		"job":     "myapp",
+		"logger":  "github.com/someone/project",
+		"level":   "info",
+		"service": "api",
+	}, streams[0].Stream)
+
+	// entries in stream are ordered by time
+	assert.Len(t, streams[0].Values, 2)
+	assert.Equal(t, fmt.Sprint(now.UnixNano()), streams[0].Values[0][0])
+
+	for i, expected := range []string{"first", "second"} {
+		log := golog.Log{}
+		assert.Nil(t, json.Unmarshal([]byte(streams[0].Values[i][1]), &log))
+		assert.Equal(t, expected, log.Message)
+	}
+
+	assert.Equal(t, "error", streams[1].Stream["level"])
+	assert.Equal(t, "5", streams[1].Stream["request_id"])
+}
+
+func TestLokiDisabledLabels(t *testing.T) {
+	appender := Loki(golog.Conf{
+		"logger_label": "",
+		"level_label":  "severity",
+	})
+	defer appender.Close()
+
+	labels := appender.Labels(golog.Log{
+		Level:  golog.WARN,
+		Logger: &golog.Logger{Name: "somelogger"},
+	})
+
+	assert.Equal(t, map[string]string{"severity": "warn"}, labels)
+}
+
+func TestLokiDefaultLabel(t *testing.T) {
+	job := filepath.Base(os.Args[0])
+
+	// job label is added if there would be no other static labels
+	appender := Loki(golog.Conf{
+		"logger_label": "",
+		"level_label":  "",
+		"labels":       "user",
+	})
+	defer appender.Close()
+
+	labels := appender.Labels(golog.Log{Level: golog.WARN, Ctx: golog.Ctx{"user": "john"}})
+	assert.Equal(t, map[string]string{"job": job, "user": "john"}, labels)
+
+	// and if log doesn't have any of configured labels
+	appender = Loki(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, map[string]string{"job": job}, appender.Labels(golog.Log{}))
+}
+
+func TestLokiCardinalityFold(t *testing.T) {
+	appender := Loki(golog.Conf{
+		"labels":           "user",
+		"level_label":      "",
+		"max_label_values": "2",
+	})
+	defer appender.Close()
+
+	user := func(name string) string {
+		return appender.Labels(golog.Log{Ctx: golog.Ctx{"user": name}})["user"]
+	}
+
+	assert.Equal(t, "john", user("john"))
+	assert.Equal(t, "jane", user("jane"))
+	assert.Equal(t, LokiOtherValue, user("jack"))
+
+	// already known values are still used
+	assert.Equal(t, "john", user("john"))
+}
+
+func TestLokiCardinalityDrop(t *testing.T) {
+	appender := Loki(golog.Conf{
+		"labels":           "user",
+		"level_label":      "",
+		"max_label_values": "1",
+		"cardinality":      "drop",
+	})
+	defer appender.Close()
+
+	labels := appender.Labels(golog.Log{Ctx: golog.Ctx{"user": "john"}})
+	assert.Equal(t, map[string]string{"user": "john"}, labels)
+
+	// after limit is reached, label is not used anymore,
+	// and stream gets default job label
+	job := map[string]string{"job": filepath.Base(os.Args[0])}
+	labels = appender.Labels(golog.Log{Ctx: golog.Ctx{"user": "jane"}})
+	assert.Equal(t, job, labels)
+
+	labels = appender.Labels(golog.Log{Ctx: golog.Ctx{"user": "john"}})
+	assert.Equal(t, job, labels)
+}
+
+func TestLokiRetry(t *testing.T) {
+	ls := &lokiServer{statuses: []int{500, 429}}
+	srv := httptest.NewServer(ls)
+	defer srv.Close()
+
+	appender := Loki(golog.Conf{
+		"url":     srv.URL,
+		"backoff": "1ms",
+	})
+
+	appender.Append(golog.Log{Message: "some message"})
+	assert.Nil(t, appender.Close())
+
+	streams := ls.streams()
+	assert.Len(t, streams, 1)
+	assert.Len(t, ls.headers, 3)
+}
+
+func TestLokiLabel(t *testing.T) {
+	assert.Equal(t, "request_id", lokiLabel("request-id"))
+	assert.Equal(t, "_1st", lokiLabel("1st"))
+	assert.Equal(t, "some_key", lokiLabel("some.key"))
+}
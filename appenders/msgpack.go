@@ -0,0 +1,449 @@
+package appenders
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Minimal MessagePack encoder and decoder, with support for
+// types which are needed by Fluentd forward protocol.
+
+var errMsgpackInvalid = errors.New("msgpack: invalid data")
+
+// max size of decoded string or binary value, to protect from invalid data
+const msgpackMaxSize = 64 * 1024 * 1024
+
+// MessagePack extension value, used for values which decoder doesn't know.
+type msgpackExt struct {
+	Type int8
+	Data []byte
+}
+
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) bytes() []byte {
+	return e.buf
+}
+
+func (e *msgpackEncoder) writeNil() {
+	e.buf = append(e.buf, 0xc0)
+}
+
+func (e *msgpackEncoder) writeBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 0xc3)
+	} else {
+		e.buf = append(e.buf, 0xc2)
+	}
+}
+
+func (e *msgpackEncoder) writeInt(v int64) {
+	switch {
+	case v >= 0:
+		e.writeUint(uint64(v))
+	case v >= -32:
+		e.buf = append(e.buf, byte(v))
+	case v >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+	case v >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+	}
+}
+
+func (e *msgpackEncoder) writeUint(v uint64) {
+	switch {
+	case v <= 0x7f:
+		e.buf = append(e.buf, byte(v))
+	case v <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+	case v <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+	}
+}
+
+func (e *msgpackEncoder) writeFloat(v float64) {
+	e.buf = append(e.buf, 0xcb)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+func (e *msgpackEncoder) writeString(v string) {
+	n := len(v)
+	switch {
+	case n <= 31:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+
+	e.buf = append(e.buf, v...)
+}
+
+func (e *msgpackEncoder) writeBinary(v []byte) {
+	n := len(v)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xc5)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xc6)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+
+	e.buf = append(e.buf, v...)
+}
+
+func (e *msgpackEncoder) writeArrayHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xdc)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdd)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) writeMapHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xde)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdf)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+// Writing time as Fluentd EventTime, which is extension type 0
+// with seconds and nanoseconds as two big endian 32 bit integers.
+func (e *msgpackEncoder) writeEventTime(t time.Time) {
+	e.buf = append(e.buf, 0xd7, 0x00)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Unix()))
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Nanosecond()))
+}
+
+func (e *msgpackEncoder) writeMap(m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	e.writeMapHeader(len(keys))
+	for _, k := range keys {
+		e.writeString(k)
+		e.write(m[k])
+	}
+}
+
+// Writing any value. Values which cannot be represented in MessagePack
+// are written as strings.
+func (e *msgpackEncoder) write(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		e.writeNil()
+	case bool:
+		e.writeBool(v)
+	case int:
+		e.writeInt(int64(v))
+	case int8:
+		e.writeInt(int64(v))
+	case int16:
+		e.writeInt(int64(v))
+	case int32:
+		e.writeInt(int64(v))
+	case int64:
+		e.writeInt(v)
+	case uint:
+		e.writeUint(uint64(v))
+	case uint8:
+		e.writeUint(uint64(v))
+	case uint16:
+		e.writeUint(uint64(v))
+	case uint32:
+		e.writeUint(uint64(v))
+	case uint64:
+		e.writeUint(v)
+	case float32:
+		e.writeFloat(float64(v))
+	case float64:
+		e.writeFloat(v)
+	case string:
+		e.writeString(v)
+	case []byte:
+		e.writeBinary(v)
+	case time.Time:
+		e.writeString(v.Format(time.RFC3339Nano))
+	case error:
+		e.writeString(v.Error())
+	case fmt.Stringer:
+		e.writeString(v.String())
+	case golog.Ctx:
+		e.writeMap(v)
+	case map[string]interface{}:
+		e.writeMap(v)
+	case []interface{}:
+		e.writeArrayHeader(len(v))
+		for _, item := range v {
+			e.write(item)
+		}
+	default:
+		e.writeReflect(value)
+	}
+}
+
+func (e *msgpackEncoder) writeReflect(value interface{}) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		e.writeArrayHeader(rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			e.write(rv.Index(i).Interface())
+		}
+
+		return
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			m := make(map[string]interface{}, rv.Len())
+			for _, k := range rv.MapKeys() {
+				m[k.String()] = rv.MapIndex(k).Interface()
+			}
+
+			e.writeMap(m)
+			return
+		}
+	case reflect.Ptr:
+		if rv.IsNil() {
+			e.writeNil()
+			return
+		}
+	}
+
+	// other values are written as JSON
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		e.writeString(fmt.Sprint(value))
+		return
+	}
+
+	e.writeString(string(encoded))
+}
+
+// Decoding MessagePack values from reader.
+// Only maps with string keys are supported, and they are decoded as map[string]interface{}.
+// Integers are decoded as int64, or uint64 if they are too big,
+// and extension values as msgpackExt.
+type msgpackDecoder struct {
+	r *bufio.Reader
+}
+
+func newMsgpackDecoder(r io.Reader) *msgpackDecoder {
+	return &msgpackDecoder{bufio.NewReader(r)}
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n > msgpackMaxSize {
+		return nil, errMsgpackInvalid
+	}
+
+	buf := make([]byte, n)
+	_, err := io.ReadFull(d.r, buf)
+	return buf, err
+}
+
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	buf, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+
+	switch size {
+	case 1:
+		return uint64(buf[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(buf)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(buf)), nil
+	}
+
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xe0 == 0xa0:
+		return d.str(int(b & 0x1f))
+	case b&0xf0 == 0x90:
+		return d.array(int(b & 0x0f))
+	case b&0xf0 == 0x80:
+		return d.mapValue(int(b & 0x0f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.readUint(1 << (b - 0xcc))
+		if err != nil || v > math.MaxInt64 {
+			return v, err
+		}
+
+		return int64(v), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		v, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+
+		shift := uint(64 - 8*size)
+		return int64(v<<shift) >> shift, nil
+	case 0xca:
+		v, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.readUint(8)
+		return math.Float64frombits(v), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.str(int(n))
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.read(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.mapValue(int(n))
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (b - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.readUint(1 << (b - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.ext(int(n))
+	}
+
+	return nil, errMsgpackInvalid
+}
+
+func (d *msgpackDecoder) str(n int) (interface{}, error) {
+	buf, err := d.read(n)
+	return string(buf), err
+}
+
+func (d *msgpackDecoder) array(n int) (interface{}, error) {
+	values := []interface{}{}
+	for i := 0; i < n; i++ {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+func (d *msgpackDecoder) mapValue(n int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for i := 0; i < n; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := k.(string)
+		if !ok {
+			return nil, errMsgpackInvalid
+		}
+
+		if m[key], err = d.decode(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (d *msgpackDecoder) ext(n int) (interface{}, error) {
+	t, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := d.read(n)
+	return msgpackExt{int8(t), data}, err
+}
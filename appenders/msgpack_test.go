@@ -0,0 +1,74 @@
+package appenders
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func msgpackRoundTrip(t *testing.T, value interface{}) interface{} {
+	e := &msgpackEncoder{}
+	e.write(value)
+
+	decoded, err := newMsgpackDecoder(bytes.NewReader(e.bytes())).decode()
+	assert.Nil(t, err)
+	return decoded
+}
+
+func TestMsgpackEncoding(t *testing.T) {
+	e := &msgpackEncoder{}
+	e.write(map[string]interface{}{"a": 1, "b": []interface{}{true, nil}})
+	assert.Equal(t, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x92, 0xc3, 0xc0}, e.bytes())
+
+	e = &msgpackEncoder{}
+	e.writeEventTime(time.Unix(1, 2))
+	assert.Equal(t, []byte{0xd7, 0x00, 0, 0, 0, 1, 0, 0, 0, 2}, e.bytes())
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	for _, n := range []int64{0, 1, 127, 128, 255, 256, 65535, 65536, math.MaxUint32, math.MaxUint32 + 1,
+		-1, -32, -33, -128, -129, -32768, -32769, math.MinInt32, math.MinInt32 - 1} {
+		assert.Equal(t, n, msgpackRoundTrip(t, n))
+	}
+
+	// integers which don't fit in int64 are decoded as uint64
+	assert.Equal(t, uint64(math.MaxUint64), msgpackRoundTrip(t, uint64(math.MaxUint64)))
+
+	for _, s := range []string{"", "short", strings.Repeat("a", 31), strings.Repeat("a", 200), strings.Repeat("a", 70000)} {
+		assert.Equal(t, s, msgpackRoundTrip(t, s))
+	}
+
+	assert.Equal(t, 1.5, msgpackRoundTrip(t, 1.5))
+	assert.Equal(t, float64(float32(2.5)), msgpackRoundTrip(t, float32(2.5)))
+	assert.Equal(t, []byte{1, 2, 3}, msgpackRoundTrip(t, []byte{1, 2, 3}))
+	assert.Equal(t, "some error", msgpackRoundTrip(t, errors.New("some error")))
+	assert.Equal(t, `{"A":1}`, msgpackRoundTrip(t, struct{ A int }{1}))
+	assert.Equal(t, []interface{}{"a", "b"}, msgpackRoundTrip(t, []string{"a", "b"}))
+	assert.Equal(t, map[string]interface{}{"key": "value"}, msgpackRoundTrip(t, golog.Ctx{"key": "value"}))
+
+	big := make([]interface{}, 20)
+	for i := range big {
+		big[i] = int64(i)
+	}
+
+	assert.Equal(t, big, msgpackRoundTrip(t, big))
+}
+
+func TestMsgpackInvalid(t *testing.T) {
+	_, err := newMsgpackDecoder(bytes.NewReader([]byte{0xc1})).decode()
+	assert.Equal(t, errMsgpackInvalid, err)
+
+	// map with integer key
+	_, err = newMsgpackDecoder(bytes.NewReader([]byte{0x81, 0x01, 0x01})).decode()
+	assert.Equal(t, errMsgpackInvalid, err)
+
+	// truncated string
+	_, err = newMsgpackDecoder(bytes.NewReader([]byte{0xa5, 'a'})).decode()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,321 @@
+package appenders
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+const (
+	// every message ends with new line
+	FramingNewline = "newline"
+
+	// every message starts with 4 bytes big endian length
+	FramingLength = "length"
+)
+
+var (
+	errNetworkBufferFull = errors.New("network: buffer is full, oldest log is dropped")
+	errNetworkClosed     = errors.New("network: appender is closed")
+)
+
+// Representing appender which sends encoded logs over raw tcp or udp connection.
+// Logs are sent from background goroutine, so slow or dead peer never blocks logging.
+// While appender is disconnected, logs are kept in bounded buffer.
+// If buffer is full, oldest logs are dropped.
+// Failover and round-robin appenders skip appender while it cannot send logs.
+type NetworkAppender struct {
+	network string
+	address string
+	framing string
+	encode  func(golog.Log) ([]byte, error)
+	tls     *tls.Config
+	timeout time.Duration
+	backoff backoff
+
+	queue chan []byte
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	mu      sync.RWMutex
+	closed  bool
+	failure error
+
+	watchers deliveryWatchers
+}
+
+// github.com/ivpusic/golog/appenders/network
+func (na *NetworkAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/network"
+}
+
+func (na *NetworkAppender) Append(log golog.Log) {
+	if err := na.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will put log into buffer of appender.
+// Error is returned only if log is not accepted, because it cannot be encoded
+// or appender is closed. If buffer is full, oldest log is dropped and error is printed.
+func (na *NetworkAppender) TryAppend(log golog.Log) error {
+	msg, err := na.encode(log)
+	if err != nil {
+		return err
+	}
+
+	msg = na.frame(msg)
+
+	na.mu.RLock()
+	defer na.mu.RUnlock()
+
+	if na.closed {
+		return errNetworkClosed
+	}
+
+	for {
+		select {
+		case na.queue <- msg:
+			return nil
+		default:
+		}
+
+		// drop oldest log and try again
+		select {
+		case <-na.queue:
+			fmt.Println(errNetworkBufferFull.Error())
+		default:
+		}
+	}
+}
+
+func (na *NetworkAppender) frame(msg []byte) []byte {
+	if na.framing == FramingLength {
+		framed := make([]byte, 4, len(msg)+4)
+		binary.BigEndian.PutUint32(framed, uint32(len(msg)))
+		return append(framed, msg...)
+	}
+
+	return append(msg, '\n')
+}
+
+// Will send buffered logs until appender is closed.
+func (na *NetworkAppender) run() {
+	defer na.wg.Done()
+
+	var conn net.Conn
+	failing := false
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	for {
+		var msg []byte
+		select {
+		case msg = <-na.queue:
+		case <-na.done:
+			na.drain(conn)
+			return
+		}
+
+		for {
+			if conn == nil {
+				var err error
+				if conn, err = na.dial(); err != nil {
+					fmt.Println(err.Error())
+					na.setFailure(err)
+					failing = true
+
+					select {
+					case <-time.After(na.backoff.next()):
+						continue
+					case <-na.done:
+						return
+					}
+				}
+
+				na.backoff.reset()
+			}
+
+			if err := na.write(conn, msg); err != nil {
+				fmt.Println(err.Error())
+				na.setFailure(err)
+				failing = true
+				conn.Close()
+				conn = nil
+				continue
+			}
+
+			if failing {
+				na.setFailure(nil)
+				failing = false
+			}
+
+			break
+		}
+	}
+}
+
+// Will remember error while logs cannot be sent, and notify composite appenders about it.
+// Logs are kept in buffer until they are sent, so they are not passed to composite appenders.
+func (na *NetworkAppender) setFailure(err error) {
+	na.mu.Lock()
+	na.failure = err
+	na.mu.Unlock()
+
+	if err != nil {
+		na.watchers.notify(err, nil)
+	}
+}
+
+func (na *NetworkAppender) deliveryError() error {
+	na.mu.RLock()
+	defer na.mu.RUnlock()
+
+	return na.failure
+}
+
+func (na *NetworkAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	na.watchers.add(fn)
+}
+
+// Sending logs which are still in buffer when appender is closed.
+// Logs are sent only if appender is connected.
+func (na *NetworkAppender) drain(conn net.Conn) {
+	if conn == nil {
+		return
+	}
+
+	for {
+		select {
+		case msg := <-na.queue:
+			if err := na.write(conn, msg); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (na *NetworkAppender) write(conn net.Conn, msg []byte) error {
+	if na.timeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(na.timeout))
+	}
+
+	_, err := conn.Write(msg)
+	return err
+}
+
+func (na *NetworkAppender) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: na.timeout}
+	if na.tls != nil {
+		return tls.DialWithDialer(dialer, na.network, na.address, na.tls)
+	}
+
+	return dialer.Dial(na.network, na.address)
+}
+
+// Will stop appender. Buffered logs are sent if appender is connected.
+func (na *NetworkAppender) Close() error {
+	na.mu.Lock()
+	if na.closed {
+		na.mu.Unlock()
+		return nil
+	}
+
+	na.closed = true
+	na.mu.Unlock()
+
+	close(na.done)
+	na.wg.Wait()
+	return nil
+}
+
+// Will make TLS configuration from appender configuration.
+// If TLS is not enabled, nil is returned.
+func tlsConf(cnf golog.Conf) (*tls.Config, error) {
+	if !confBool(cnf, "tls", false) {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         cnf["tls_server_name"],
+		InsecureSkipVerify: confBool(cnf, "tls_skip_verify", false),
+	}
+
+	// in case of invalid CA file, configuration is still returned,
+	// so logs are never sent over unencrypted connection
+	if path := cnf["tls_ca"]; len(path) > 0 {
+		config.RootCAs = x509.NewCertPool()
+
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return config, err
+		}
+
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return config, errors.New("no certificates found in " + path)
+		}
+	}
+
+	return config, nil
+}
+
+// Function for creating network appender.
+// Connection is made from background goroutine, so this function never blocks.
+// Supported configuration keys are:
+// network - tcp or udp (default tcp)
+// address - address of log collector
+// framing - newline or length (default newline)
+// encoding - json or text (default json)
+// buffer - max number of buffered logs (default 1000)
+// timeout - timeout for connecting and writing (default 5s)
+// backoff - first delay between reconnects (default 100ms)
+// max_backoff - max delay between reconnects (default 30s)
+// tls - use TLS connection (default false)
+// tls_ca - path of PEM file with CA certificates
+// tls_server_name - server name used for verifying certificate
+// tls_skip_verify - don't verify server certificate (default false)
+func Network(cnf golog.Conf) *NetworkAppender {
+	config, err := tlsConf(cnf)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+
+	size := confInt(cnf, "buffer", 1000)
+	if size <= 0 {
+		size = 1000
+	}
+
+	na := &NetworkAppender{
+		network: confString(cnf, "network", "tcp"),
+		address: cnf["address"],
+		framing: confString(cnf, "framing", FramingNewline),
+		encode:  encoding(cnf["encoding"]),
+		tls:     config,
+		timeout: confDuration(cnf, "timeout", 5*time.Second),
+		backoff: backoff{
+			min: confDuration(cnf, "backoff", 100*time.Millisecond),
+			max: confDuration(cnf, "max_backoff", 30*time.Second),
+		},
+		queue: make(chan []byte, size),
+		done:  make(chan struct{}),
+	}
+
+	na.wg.Add(1)
+	go na.run()
+
+	return na
+}
@@ -0,0 +1,232 @@
+package appenders
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Will accept connections and send received newline framed messages to channel.
+func serveLines(ln net.Listener, received chan string) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				received <- scanner.Text()
+			}
+		}()
+	}
+}
+
+func receive(t *testing.T, received chan string) string {
+	select {
+	case msg := <-received:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not received")
+		return ""
+	}
+}
+
+func TestNetworkId(t *testing.T) {
+	appender := Network(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/network", appender.Id())
+}
+
+func TestNetworkNewline(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	received := make(chan string, 10)
+	go serveLines(ln, received)
+
+	appender := Network(golog.Conf{
+		"address": ln.Addr().String(),
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first message"})
+	appender.Append(golog.Log{Message: "second message"})
+
+	for _, expected := range []string{"first message", "second message"} {
+		log := golog.Log{}
+		assert.Nil(t, json.Unmarshal([]byte(receive(t, received)), &log))
+		assert.Equal(t, expected, log.Message)
+	}
+}
+
+func TestNetworkLengthFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	appender := Network(golog.Conf{
+		"address":  ln.Addr().String(),
+		"framing":  "length",
+		"encoding": "text",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "some message", Level: golog.INFO, Ctx: golog.Ctx{"key": "value"}})
+
+	conn, err := ln.Accept()
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	var length uint32
+	assert.Nil(t, binary.Read(conn, binary.BigEndian, &length))
+
+	msg := make([]byte, length)
+	_, err = io.ReadFull(conn, msg)
+	assert.Nil(t, err)
+	assert.Equal(t, "0001-01-01T00:00:00Z INFO some message key=value", string(msg))
+}
+
+func TestNetworkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	appender := Network(golog.Conf{
+		"network": "udp",
+		"address": conn.LocalAddr().String(),
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "some message"})
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+
+	log := golog.Log{}
+	assert.Nil(t, json.Unmarshal(buf[:n], &log))
+	assert.Equal(t, "some message", log.Message)
+}
+
+func TestNetworkReconnectBuffer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	appender := Network(golog.Conf{
+		"address":     address,
+		"buffer":      "2",
+		"backoff":     "10ms",
+		"max_backoff": "50ms",
+	})
+	defer appender.Close()
+
+	// first log is taken by sender which is waiting for connection,
+	// and only two newest logs are kept in buffer
+	appender.Append(golog.Log{Message: "message 1"})
+	time.Sleep(50 * time.Millisecond)
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "message 2"}))
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "message 3"}))
+	assert.Nil(t, appender.TryAppend(golog.Log{Message: "message 4"}))
+
+	ln, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skip("cannot listen on the same address again")
+	}
+	defer ln.Close()
+
+	received := make(chan string, 10)
+	go serveLines(ln, received)
+
+	for _, expected := range []string{"message 1", "message 3", "message 4"} {
+		log := golog.Log{}
+		assert.Nil(t, json.Unmarshal([]byte(receive(t, received)), &log))
+		assert.Equal(t, expected, log.Message)
+	}
+}
+
+func TestNetworkDeadPeer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	// peer accepts connection, but never reads from it
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	appender := Network(golog.Conf{
+		"address": ln.Addr().String(),
+		"buffer":  "10",
+		"timeout": "100ms",
+	})
+	defer appender.Close()
+
+	big := strings.Repeat("a", 64*1024)
+
+	// logging never waits for peer
+	for i := 0; i < 200; i++ {
+		start := time.Now()
+		appender.TryAppend(golog.Log{Message: big})
+		assert.True(t, time.Since(start) < 100*time.Millisecond)
+	}
+}
+
+func TestNetworkTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(nil)
+	srv.StartTLS()
+	certs := srv.TLS.Certificates
+	srv.Close()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: certs})
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	received := make(chan string, 10)
+	go serveLines(ln, received)
+
+	appender := Network(golog.Conf{
+		"address":         ln.Addr().String(),
+		"tls":             "true",
+		"tls_skip_verify": "true",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "some message"})
+
+	log := golog.Log{}
+	assert.Nil(t, json.Unmarshal([]byte(receive(t, received)), &log))
+	assert.Equal(t, "some message", log.Message)
+}
+
+func TestNetworkClose(t *testing.T) {
+	appender := Network(golog.Conf{
+		"address": "127.0.0.1:1",
+	})
+
+	assert.Nil(t, appender.Close())
+	assert.Nil(t, appender.Close())
+	assert.Equal(t, errNetworkClosed, appender.TryAppend(golog.Log{Message: "some message"}))
+}
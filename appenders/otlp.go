@@ -0,0 +1,370 @@
+package appenders
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Name of instrumentation scope used for logs without logger name.
+const otlpScope = "github.com/ivpusic/golog"
+
+// Types with the same JSON format as OTLP protobuf messages.
+
+type otlpValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	BytesValue  *string         `json:"bytesValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKvlist     `json:"kvlistValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpValue `json:"values"`
+}
+
+type otlpKvlist struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpValue      `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceId              string         `json:"traceId,omitempty"`
+	SpanId               string         `json:"spanId,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []otlpRecord      `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource  map[string][]otlpKeyValue `json:"resource"`
+	ScopeLogs []*otlpScopeLogs          `json:"scopeLogs"`
+}
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResponse struct {
+	PartialSuccess *struct {
+		RejectedLogRecords json.Number `json:"rejectedLogRecords"`
+		ErrorMessage       string      `json:"errorMessage"`
+	} `json:"partialSuccess"`
+}
+
+// Representing appender which exports logs to OpenTelemetry collector
+// using OTLP/HTTP protocol with JSON encoding.
+// Logs are collected in batches, and sent from background goroutine.
+//
+// Logger name is used as instrumentation scope, context and data are sent as attributes,
+// and trace and span ids are taken from context if they are present.
+type OTLPAppender struct {
+	url      string
+	resource []otlpKeyValue
+	traceKey string
+	spanKey  string
+	sender   *httpSender
+	batcher  *batcher
+}
+
+// github.com/ivpusic/golog/appenders/otlp
+func (oa *OTLPAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/otlp"
+}
+
+func (oa *OTLPAppender) Append(log golog.Log) {
+	if err := oa.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+func (oa *OTLPAppender) TryAppend(log golog.Log) error {
+	return oa.batcher.add(log)
+}
+
+// Exporting logs in one request, with retries.
+// If collector rejects some of logs, error is returned.
+func (oa *OTLPAppender) AppendBatch(logs []golog.Log) error {
+	scopes := map[string]*otlpScopeLogs{}
+	resource := otlpResourceLogs{
+		Resource: map[string][]otlpKeyValue{"attributes": oa.resource},
+	}
+
+	now := time.Now()
+	for _, log := range logs {
+		name := loggerName(log)
+		if len(name) == 0 {
+			name = otlpScope
+		}
+
+		scope, ok := scopes[name]
+		if !ok {
+			scope = &otlpScopeLogs{Scope: map[string]string{"name": name}}
+			scopes[name] = scope
+			resource.ScopeLogs = append(resource.ScopeLogs, scope)
+		}
+
+		scope.LogRecords = append(scope.LogRecords, oa.record(log, now))
+	}
+
+	body, err := json.Marshal(otlpRequest{ResourceLogs: []otlpResourceLogs{resource}})
+	if err != nil {
+		return err
+	}
+
+	respBody, err := oa.sender.send("POST", oa.url, "application/json", body)
+	if err != nil {
+		return err
+	}
+
+	resp := otlpResponse{}
+	if len(respBody) > 0 && json.Unmarshal(respBody, &resp) == nil && resp.PartialSuccess != nil {
+		rejected, _ := resp.PartialSuccess.RejectedLogRecords.Int64()
+		if rejected > 0 {
+			return fmt.Errorf("otlp: %d of %d logs are rejected: %s", rejected, len(logs), resp.PartialSuccess.ErrorMessage)
+		}
+	}
+
+	return nil
+}
+
+// Will convert log to OTLP log record.
+func (oa *OTLPAppender) record(log golog.Log, observed time.Time) otlpRecord {
+	timestamp := log.Time
+	if timestamp.IsZero() {
+		timestamp = observed
+	}
+
+	record := otlpRecord{
+		TimeUnixNano:         strconv.FormatInt(timestamp.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(observed.UnixNano(), 10),
+		SeverityNumber:       otlpSeverity(log.Level),
+		SeverityText:         log.Level.Name,
+		Body:                 otlpAnyValue(log.Message),
+	}
+
+	for _, k := range sortedKeys(log.Ctx) {
+		v := log.Ctx[k]
+
+		if k == oa.traceKey {
+			if id, ok := otlpId(v, 16); ok {
+				record.TraceId = id
+				continue
+			}
+		}
+
+		if k == oa.spanKey {
+			if id, ok := otlpId(v, 8); ok {
+				record.SpanId = id
+				continue
+			}
+		}
+
+		record.Attributes = append(record.Attributes, otlpKeyValue{k, otlpAnyValue(v)})
+	}
+
+	if len(log.Data) > 0 {
+		record.Attributes = append(record.Attributes, otlpKeyValue{"data", otlpAnyValue(log.Data)})
+	}
+
+	return record
+}
+
+// Will set function which is called when batch of logs cannot be exported.
+// By default errors are printed.
+func (oa *OTLPAppender) OnError(fn func(err error, logs []golog.Log)) *OTLPAppender {
+	oa.batcher.setErrorHandler(fn)
+	return oa
+}
+
+func (oa *OTLPAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	oa.batcher.watchers.add(fn)
+}
+
+// Will send current batch, and wait until all pending batches are sent.
+func (oa *OTLPAppender) Flush() error {
+	return oa.batcher.flush()
+}
+
+// Will send all collected logs and stop appender.
+func (oa *OTLPAppender) Close() error {
+	return oa.batcher.close()
+}
+
+// Mapping golog level to OTLP severity number.
+// Levels between standard levels are mapped to severity of lower level.
+func otlpSeverity(lvl golog.Level) int {
+	switch {
+	case lvl.Value >= golog.PANIC.Value:
+		return 21
+	case lvl.Value >= golog.ERROR.Value:
+		return 17
+	case lvl.Value >= golog.WARN.Value:
+		return 13
+	case lvl.Value >= golog.INFO.Value:
+		return 9
+	case lvl.Value >= golog.DEBUG.Value:
+		return 5
+	}
+
+	return 1
+}
+
+// Trace and span ids can be hex strings or byte slices of required length.
+func otlpId(value interface{}, size int) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		if decoded, err := hex.DecodeString(v); err == nil && len(decoded) == size {
+			return strings.ToLower(v), true
+		}
+	case []byte:
+		if len(v) == size {
+			return hex.EncodeToString(v), true
+		}
+	case fmt.Stringer:
+		return otlpId(v.String(), size)
+	}
+
+	return "", false
+}
+
+// Will convert Go value to OTLP AnyValue.
+func otlpAnyValue(value interface{}) otlpValue {
+	switch v := value.(type) {
+	case nil:
+		return otlpValue{}
+	case string:
+		return otlpValue{StringValue: &v}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int, int8, int16, int32, int64:
+		i := strconv.FormatInt(reflect.ValueOf(v).Int(), 10)
+		return otlpValue{IntValue: &i}
+	case uint, uint8, uint16, uint32, uint64:
+		i := strconv.FormatUint(reflect.ValueOf(v).Uint(), 10)
+		return otlpValue{IntValue: &i}
+	case float32:
+		f := float64(v)
+		return otlpValue{DoubleValue: &f}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	case []byte:
+		b := base64.StdEncoding.EncodeToString(v)
+		return otlpValue{BytesValue: &b}
+	case error:
+		s := v.Error()
+		return otlpValue{StringValue: &s}
+	case fmt.Stringer:
+		s := v.String()
+		return otlpValue{StringValue: &s}
+	case golog.Ctx:
+		return otlpMapValue(v)
+	case map[string]interface{}:
+		return otlpMapValue(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		values := make([]otlpValue, rv.Len())
+		for i := range values {
+			values[i] = otlpAnyValue(rv.Index(i).Interface())
+		}
+
+		return otlpValue{ArrayValue: &otlpArrayValue{values}}
+	}
+
+	// other values are sent as JSON
+	encoded, err := json.Marshal(value)
+	s := string(encoded)
+	if err != nil {
+		s = fmt.Sprint(value)
+	}
+
+	return otlpValue{StringValue: &s}
+}
+
+func otlpMapValue(m map[string]interface{}) otlpValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	values := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, otlpKeyValue{k, otlpAnyValue(m[k])})
+	}
+
+	return otlpValue{KvlistValue: &otlpKvlist{values}}
+}
+
+// Function for creating OTLP appender.
+// Supported configuration keys are:
+// url - OTLP/HTTP logs endpoint (default http://127.0.0.1:4318/v1/logs)
+// service_name - value of service.name resource attribute (default name of executable)
+// resource.<key> - resource attribute, for example "resource.deployment.environment"
+// trace_key - context key with trace id (default trace_id)
+// span_key - context key with span id (default span_id)
+// Batching, retries, headers, gzip and timeout are configured
+// using the same keys as in HTTP appender, but gzip is false by default.
+func OTLP(cnf golog.Conf) *OTLPAppender {
+	attributes := map[string]string{
+		"service.name": confString(cnf, "service_name", filepath.Base(os.Args[0])),
+	}
+
+	for k, v := range cnf {
+		if strings.HasPrefix(k, "resource.") {
+			attributes[k[len("resource."):]] = v
+		}
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	resource := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		resource = append(resource, otlpKeyValue{k, otlpAnyValue(attributes[k])})
+	}
+
+	oa := &OTLPAppender{
+		url:      confString(cnf, "url", "http://127.0.0.1:4318/v1/logs"),
+		resource: resource,
+		traceKey: confString(cnf, "trace_key", "trace_id"),
+		spanKey:  confString(cnf, "span_key", "span_id"),
+		sender:   newHTTPSender(cnf, false),
+	}
+
+	oa.batcher = newBatcher(cnf, oa.AppendBatch)
+
+	return oa
+}
@@ -0,0 +1,178 @@
+package appenders
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// OTLP collector which remembers received requests
+type otlpCollector struct {
+	mu       sync.Mutex
+	requests []map[string]interface{}
+	response string
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req := map[string]interface{}{}
+	json.NewDecoder(r.Body).Decode(&req)
+	c.requests = append(c.requests, req)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(c.response))
+}
+
+func (c *otlpCollector) received() []map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.requests
+}
+
+// Will return value at path in decoded JSON, where path contains map keys and slice indexes.
+func jsonPath(value interface{}, path ...interface{}) interface{} {
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			value = value.(map[string]interface{})[key]
+		case int:
+			value = value.([]interface{})[key]
+		}
+	}
+
+	return value
+}
+
+func TestOTLPId(t *testing.T) {
+	appender := OTLP(golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/otlp", appender.Id())
+}
+
+func TestOTLPExport(t *testing.T) {
+	collector := &otlpCollector{response: "{}"}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	appender := OTLP(golog.Conf{
+		"url":                             srv.URL + "/v1/logs",
+		"service_name":                    "myservice",
+		"resource.deployment.environment": "production",
+		"flush_interval":                  "1h",
+	})
+	defer appender.Close()
+
+	now := time.Unix(1700000000, 5)
+	appender.Append(golog.Log{
+		Message: "some message",
+		Level:   golog.WARN,
+		Time:    now,
+		Logger:  &golog.Logger{Name: "somelogger"},
+		Ctx: golog.Ctx{
+			"trace_id": "5B8EFFF798038103D269B633813FC60C",
+			"span_id":  "eee19b7ec3c1b174",
+			"user":     "john",
+			"count":    5,
+		},
+		Data: []interface{}{1.5, true},
+	})
+	appender.Append(golog.Log{Message: "other message", Level: golog.ERROR})
+	assert.Nil(t, appender.Flush())
+
+	requests := collector.received()
+	assert.Len(t, requests, 1)
+
+	resource := jsonPath(requests[0], "resourceLogs", 0)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "deployment.environment", "value": map[string]interface{}{"stringValue": "production"}},
+		map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "myservice"}},
+	}, jsonPath(resource, "resource", "attributes"))
+
+	assert.Equal(t, "somelogger", jsonPath(resource, "scopeLogs", 0, "scope", "name"))
+	assert.Equal(t, otlpScope, jsonPath(resource, "scopeLogs", 1, "scope", "name"))
+
+	record := jsonPath(resource, "scopeLogs", 0, "logRecords", 0)
+	assert.Equal(t, "1700000000000000005", jsonPath(record, "timeUnixNano"))
+	assert.Equal(t, float64(13), jsonPath(record, "severityNumber"))
+	assert.Equal(t, "WARN", jsonPath(record, "severityText"))
+	assert.Equal(t, "some message", jsonPath(record, "body", "stringValue"))
+	assert.Equal(t, "5b8efff798038103d269b633813fc60c", jsonPath(record, "traceId"))
+	assert.Equal(t, "eee19b7ec3c1b174", jsonPath(record, "spanId"))
+
+	// attributes are sorted by key, and trace and span ids are not attributes
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "count", "value": map[string]interface{}{"intValue": "5"}},
+		map[string]interface{}{"key": "user", "value": map[string]interface{}{"stringValue": "john"}},
+		map[string]interface{}{"key": "data", "value": map[string]interface{}{"arrayValue": map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{"doubleValue": 1.5},
+				map[string]interface{}{"boolValue": true},
+			},
+		}}},
+	}, jsonPath(record, "attributes"))
+
+	record = jsonPath(resource, "scopeLogs", 1, "logRecords", 0)
+	assert.Equal(t, float64(17), jsonPath(record, "severityNumber"))
+	assert.Nil(t, jsonPath(record, "traceId"))
+}
+
+func TestOTLPPartialSuccess(t *testing.T) {
+	collector := &otlpCollector{response: `{"partialSuccess":{"rejectedLogRecords":"1","errorMessage":"too old"}}`}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	appender := OTLP(golog.Conf{"url": srv.URL})
+	defer appender.Close()
+
+	err := appender.AppendBatch([]golog.Log{{Message: "first"}, {Message: "second"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "otlp: 1 of 2 logs are rejected: too old", err.Error())
+}
+
+func TestOTLPSeverity(t *testing.T) {
+	assert.Exactly(t, 5, otlpSeverity(golog.DEBUG))
+	assert.Exactly(t, 9, otlpSeverity(golog.INFO))
+	assert.Exactly(t, 13, otlpSeverity(golog.WARN))
+	assert.Exactly(t, 17, otlpSeverity(golog.ERROR))
+	assert.Exactly(t, 21, otlpSeverity(golog.PANIC))
+	assert.Exactly(t, 9, otlpSeverity(golog.Level{Value: 25, Name: "NOTICE"}))
+	assert.Exactly(t, 1, otlpSeverity(golog.Level{Value: 5, Name: "TRACE"}))
+}
+
+func TestOTLPAnyValue(t *testing.T) {
+	encode := func(value interface{}) string {
+		encoded, _ := json.Marshal(otlpAnyValue(value))
+		return string(encoded)
+	}
+
+	assert.Equal(t, `{"intValue":"7"}`, encode(uint8(7)))
+	assert.Equal(t, `{"bytesValue":"AQI="}`, encode([]byte{1, 2}))
+	assert.Equal(t, `{"stringValue":"some error"}`, encode(errors.New("some error")))
+	assert.Equal(t, `{"kvlistValue":{"values":[{"key":"a","value":{"intValue":"1"}}]}}`, encode(golog.Ctx{"a": 1}))
+	assert.Equal(t, `{"stringValue":"{\"A\":1}"}`, encode(struct{ A int }{1}))
+	assert.Equal(t, `{}`, encode(nil))
+}
+
+func TestOTLPTraceId(t *testing.T) {
+	id, ok := otlpId([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 8)
+	assert.True(t, ok)
+	assert.Equal(t, "0102030405060708", id)
+
+	// invalid ids are sent as attributes
+	_, ok = otlpId("notanid", 8)
+	assert.False(t, ok)
+
+	_, ok = otlpId("0102", 8)
+	assert.False(t, ok)
+}
@@ -0,0 +1,238 @@
+package appenders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Representing query of logs kept by ring appender.
+// Empty fields are not used for filtering.
+type RingQuery struct {
+	// logs made before this time are skipped
+	From time.Time
+
+	// logs made at this time or after it are skipped
+	To time.Time
+
+	// minimum level of log
+	Level golog.Level
+
+	// name of logger which made log
+	Logger string
+
+	// all keys and values have to be present in log context
+	Ctx golog.Ctx
+
+	// log message has to contain this string
+	Message string
+
+	// if set, only this number of newest matching logs is returned
+	Limit int
+}
+
+func (q RingQuery) match(log golog.Log) bool {
+	if !q.From.IsZero() && log.Time.Before(q.From) {
+		return false
+	}
+
+	if !q.To.IsZero() && !log.Time.Before(q.To) {
+		return false
+	}
+
+	if log.Level.Value < q.Level.Value {
+		return false
+	}
+
+	if len(q.Logger) > 0 && loggerName(log) != q.Logger {
+		return false
+	}
+
+	if len(q.Message) > 0 && !strings.Contains(log.Message, q.Message) {
+		return false
+	}
+
+	for k, v := range q.Ctx {
+		value, ok := log.Ctx[k]
+		if !ok || !reflect.DeepEqual(v, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type ringEntry struct {
+	log  golog.Log
+	size int
+}
+
+// Representing appender which keeps last logs in memory.
+// Number of kept logs is limited, and optionally size of kept logs is limited too.
+// When limit is reached, oldest logs are removed.
+type RingAppender struct {
+	mu      sync.RWMutex
+	entries []ringEntry
+
+	// index of oldest entry
+	start int
+
+	// number of kept entries
+	count int
+
+	// size of kept entries in bytes, and max allowed size
+	// size of entry is length of its JSON representation
+	bytes    int
+	maxBytes int
+}
+
+// github.com/ivpusic/golog/appenders/ring
+func (ra *RingAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/ring"
+}
+
+func (ra *RingAppender) Append(log golog.Log) {
+	log = detachLog(log)
+
+	size := 0
+	if ra.maxBytes > 0 {
+		size = logSize(log)
+
+		// log which cannot fit at all is not kept
+		if size > ra.maxBytes {
+			return
+		}
+	}
+
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	if ra.count == len(ra.entries) {
+		ra.removeOldest()
+	}
+
+	for ra.maxBytes > 0 && ra.count > 0 && ra.bytes+size > ra.maxBytes {
+		ra.removeOldest()
+	}
+
+	ra.entries[(ra.start+ra.count)%len(ra.entries)] = ringEntry{log, size}
+	ra.count++
+	ra.bytes += size
+}
+
+func (ra *RingAppender) removeOldest() {
+	ra.bytes -= ra.entries[ra.start].size
+	ra.entries[ra.start] = ringEntry{}
+	ra.start = (ra.start + 1) % len(ra.entries)
+	ra.count--
+}
+
+// Will return logs matching query, ordered from oldest to newest.
+func (ra *RingAppender) Query(q RingQuery) []golog.Log {
+	ra.mu.RLock()
+	defer ra.mu.RUnlock()
+
+	logs := []golog.Log{}
+
+	// go from newest to oldest, so limit can be applied
+	for i := ra.count - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(logs) == q.Limit {
+			break
+		}
+
+		log := ra.entries[(ra.start+i)%len(ra.entries)].log
+		if q.match(log) {
+			logs = append(logs, log)
+		}
+	}
+
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+
+	return logs
+}
+
+// Will return all kept logs, ordered from oldest to newest.
+func (ra *RingAppender) Logs() []golog.Log {
+	return ra.Query(RingQuery{})
+}
+
+// Will write logs matching query to provided writer as JSON lines,
+// in the same format as file appender.
+func (ra *RingAppender) Snapshot(w io.Writer, q RingQuery) error {
+	enc := json.NewEncoder(w)
+	for _, log := range ra.Query(q) {
+		if err := enc.Encode(log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Will return number of kept logs.
+func (ra *RingAppender) Len() int {
+	ra.mu.RLock()
+	defer ra.mu.RUnlock()
+
+	return ra.count
+}
+
+// Will return size of kept logs in bytes.
+// Size is accounted only if max_bytes limit is set.
+func (ra *RingAppender) Bytes() int {
+	ra.mu.RLock()
+	defer ra.mu.RUnlock()
+
+	return ra.bytes
+}
+
+// Will remove all kept logs.
+func (ra *RingAppender) Clear() {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	ra.entries = make([]ringEntry, len(ra.entries))
+	ra.start = 0
+	ra.count = 0
+	ra.bytes = 0
+}
+
+// Will return size of JSON representation of log.
+// If log cannot be represented as JSON, size is estimated.
+func logSize(log golog.Log) int {
+	line, err := json.Marshal(log)
+	if err == nil {
+		return len(line) + 1
+	}
+
+	size := len(log.Message) + len(loggerName(log)) + 128
+	for k, v := range log.Ctx {
+		size += len(k) + len(fmt.Sprint(v))
+	}
+
+	return size + len(fmt.Sprint(log.Data...))
+}
+
+// Function for creating ring appender.
+// Supported configuration keys are:
+// size - max number of kept logs (default 1000)
+// max_bytes - max size of kept logs in bytes (default is unlimited)
+func Ring(cnf golog.Conf) *RingAppender {
+	size := confInt(cnf, "size", 1000)
+	if size <= 0 {
+		size = 1000
+	}
+
+	return &RingAppender{
+		entries:  make([]ringEntry, size),
+		maxBytes: confInt(cnf, "max_bytes", 0),
+	}
+}
@@ -0,0 +1,115 @@
+package appenders
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingId(t *testing.T) {
+	appender := Ring(golog.Conf{})
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/ring", appender.Id())
+}
+
+func TestRingSize(t *testing.T) {
+	appender := Ring(golog.Conf{
+		"size": "3",
+	})
+
+	for i := 0; i < 5; i++ {
+		appender.Append(golog.Log{Message: fmt.Sprintf("message %d", i)})
+	}
+
+	assert.Exactly(t, 3, appender.Len())
+
+	logs := appender.Logs()
+	assert.Equal(t, "message 2", logs[0].Message)
+	assert.Equal(t, "message 4", logs[2].Message)
+
+	appender.Clear()
+	assert.Exactly(t, 0, appender.Len())
+	assert.Len(t, appender.Logs(), 0)
+}
+
+func TestRingMaxBytes(t *testing.T) {
+	log := golog.Log{Message: "some message"}
+	size := logSize(log)
+
+	appender := Ring(golog.Conf{
+		"max_bytes": fmt.Sprint(size*2 + 1),
+	})
+
+	for i := 0; i < 5; i++ {
+		appender.Append(log)
+	}
+
+	assert.Exactly(t, 2, appender.Len())
+	assert.Exactly(t, size*2, appender.Bytes())
+
+	// too big log is not kept
+	appender.Append(golog.Log{Message: string(make([]byte, size*3))})
+	assert.Exactly(t, 2, appender.Len())
+}
+
+func TestRingQuery(t *testing.T) {
+	appender := Ring(golog.Conf{})
+	now := time.Now()
+
+	logger := golog.GetLogger("ring")
+	other := golog.GetLogger("other")
+
+	appender.Append(golog.Log{Time: now.Add(-time.Hour), Message: "old message", Level: golog.INFO, Logger: logger})
+	appender.Append(golog.Log{Time: now, Message: "some error", Level: golog.ERROR, Logger: logger,
+		Ctx: golog.Ctx{"user": "someone"}})
+	appender.Append(golog.Log{Time: now, Message: "some warning", Level: golog.WARN, Logger: other})
+	appender.Append(golog.Log{Time: now.Add(time.Hour), Message: "new message", Level: golog.DEBUG, Logger: other})
+
+	assert.Len(t, appender.Query(RingQuery{From: now}), 3)
+	assert.Len(t, appender.Query(RingQuery{From: now, To: now.Add(time.Minute)}), 2)
+	assert.Len(t, appender.Query(RingQuery{Level: golog.WARN}), 2)
+	assert.Len(t, appender.Query(RingQuery{Logger: "ring"}), 2)
+	assert.Len(t, appender.Query(RingQuery{Message: "message"}), 2)
+	assert.Len(t, appender.Query(RingQuery{Ctx: golog.Ctx{"user": "someone"}}), 1)
+	assert.Len(t, appender.Query(RingQuery{Ctx: golog.Ctx{"user": "else"}}), 0)
+
+	logs := appender.Query(RingQuery{Limit: 2})
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "some warning", logs[0].Message)
+	assert.Equal(t, "new message", logs[1].Message)
+}
+
+func TestRingCopiesContext(t *testing.T) {
+	appender := Ring(golog.Conf{})
+
+	ctx := golog.Ctx{"key": "value"}
+	appender.Append(golog.Log{Message: "some message", Ctx: ctx})
+	ctx["key"] = "changed"
+
+	assert.Equal(t, "value", appender.Logs()[0].Ctx["key"])
+}
+
+func TestRingSnapshot(t *testing.T) {
+	appender := Ring(golog.Conf{})
+	appender.Append(golog.Log{Message: "first", Level: golog.INFO})
+	appender.Append(golog.Log{Message: "second", Level: golog.ERROR})
+
+	buf := &bytes.Buffer{}
+	err := appender.Snapshot(buf, RingQuery{Level: golog.ERROR})
+	assert.Nil(t, err)
+
+	scanner := bufio.NewScanner(buf)
+	var msgs []string
+	for scanner.Scan() {
+		log := golog.Log{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &log))
+		msgs = append(msgs, log.Message)
+	}
+
+	assert.Equal(t, []string{"second"}, msgs)
+}
@@ -0,0 +1,369 @@
+package appenders
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Representing appender which sends logs by email.
+// Logs with level at least as configured are collected over time window,
+// and one digest email is sent for every window.
+//
+// To avoid mail storms, number of emails in rate period is limited.
+// When limit is reached, logs are collected until next email can be sent,
+// and only limited number of logs is kept in one digest.
+type SMTPAppender struct {
+	address    string
+	host       string
+	username   string
+	password   string
+	from       string
+	to         []string
+	subject    string
+	level      golog.Level
+	window     time.Duration
+	maxEmails  int
+	ratePeriod time.Duration
+	maxLogs    int
+	useTLS     bool
+	startTLS   bool
+	tls        *tls.Config
+	timeout    time.Duration
+
+	mu      sync.Mutex
+	logs    []golog.Log
+	omitted int
+	timer   *time.Timer
+	sent    []time.Time
+	closed  bool
+
+	// sending is serialized, and it is not done under lock
+	sendMu sync.Mutex
+}
+
+// github.com/ivpusic/golog/appenders/smtp
+func (sa *SMTPAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/smtp"
+}
+
+// Will add log to current digest, if it has required level.
+// Digest is sent when window which started with first log passes.
+func (sa *SMTPAppender) Append(log golog.Log) {
+	if log.Level.Value < sa.level.Value {
+		return
+	}
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if sa.closed {
+		return
+	}
+
+	if len(sa.logs) < sa.maxLogs {
+		// digest is made later from timer goroutine
+		sa.logs = append(sa.logs, detachLog(log))
+	} else {
+		sa.omitted++
+	}
+
+	if sa.timer == nil {
+		sa.timer = time.AfterFunc(sa.window, sa.windowEnd)
+	}
+}
+
+// Called when window passes. If limit of emails is reached,
+// digest is sent when next email can be sent.
+func (sa *SMTPAppender) windowEnd() {
+	sa.mu.Lock()
+	if sa.closed {
+		sa.mu.Unlock()
+		return
+	}
+
+	if wait := sa.rateLimitWait(); wait > 0 {
+		sa.timer = time.AfterFunc(wait, sa.windowEnd)
+		sa.mu.Unlock()
+		return
+	}
+
+	sa.timer = nil
+	sa.mu.Unlock()
+
+	if err := sa.Flush(); err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// Will return how long appender has to wait before sending next email.
+// Caller has to hold lock.
+func (sa *SMTPAppender) rateLimitWait() time.Duration {
+	if sa.maxEmails <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	recent := sa.sent[:0]
+	for _, t := range sa.sent {
+		if now.Sub(t) < sa.ratePeriod {
+			recent = append(recent, t)
+		}
+	}
+
+	sa.sent = recent
+	if len(recent) < sa.maxEmails {
+		return 0
+	}
+
+	return sa.ratePeriod - now.Sub(recent[0])
+}
+
+// Will send collected logs immediately, without checking rate limit.
+func (sa *SMTPAppender) Flush() error {
+	sa.sendMu.Lock()
+	defer sa.sendMu.Unlock()
+
+	sa.mu.Lock()
+	logs, omitted := sa.logs, sa.omitted
+	sa.logs, sa.omitted = nil, 0
+	if sa.timer != nil {
+		sa.timer.Stop()
+		sa.timer = nil
+	}
+
+	if len(logs) > 0 {
+		sa.sent = append(sa.sent, time.Now())
+	}
+	sa.mu.Unlock()
+
+	if len(logs) == 0 {
+		return nil
+	}
+
+	return sa.send(sa.Message(logs, omitted))
+}
+
+// Will send collected logs and stop appender.
+func (sa *SMTPAppender) Close() error {
+	err := sa.Flush()
+
+	sa.mu.Lock()
+	sa.closed = true
+	sa.mu.Unlock()
+
+	return err
+}
+
+// Will make email with digest of logs.
+func (sa *SMTPAppender) Message(logs []golog.Log, omitted int) []byte {
+	msg := &bytes.Buffer{}
+
+	total := len(logs) + omitted
+	subject := fmt.Sprintf("%s: %d log", sa.subject, total)
+	if total > 1 {
+		subject += "s"
+	}
+
+	fmt.Fprintf(msg, "From: %s\r\n", sa.from)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(sa.to, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+
+	for _, log := range logs {
+		header := []string{log.Time.Format(time.RFC3339Nano), log.Level.Name}
+		if name := loggerName(log); len(name) > 0 {
+			header = append(header, name)
+		}
+
+		msg.WriteString(strings.Join(header, " ") + "\r\n")
+		message := strings.Replace(log.Message, "\r\n", "\n", -1)
+		msg.WriteString(strings.Replace(message, "\n", "\r\n", -1) + "\r\n")
+
+		for _, k := range sortedKeys(log.Ctx) {
+			fmt.Fprintf(msg, "  %s: %s\r\n", k, smtpValue(log.Ctx[k]))
+		}
+
+		if len(log.Data) > 0 {
+			fmt.Fprintf(msg, "  data: %s\r\n", smtpValue(log.Data))
+		}
+
+		msg.WriteString("\r\n")
+	}
+
+	if omitted > 0 {
+		fmt.Fprintf(msg, "%d more logs are not included\r\n", omitted)
+	}
+
+	return msg.Bytes()
+}
+
+func smtpValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
+
+// Will send email. STARTTLS is used if server supports it and it is not disabled,
+// and authentication is made if username is configured.
+func (sa *SMTPAppender) send(msg []byte) error {
+	dialer := &net.Dialer{Timeout: sa.timeout}
+
+	var conn net.Conn
+	var err error
+	if sa.useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", sa.address, sa.tls)
+	} else {
+		conn, err = dialer.Dial("tcp", sa.address)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if sa.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(sa.timeout))
+	}
+
+	c, err := smtp.NewClient(conn, sa.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	defer c.Close()
+
+	if !sa.useTLS && sa.startTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(sa.tls); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(sa.username) > 0 {
+		auth := smtp.PlainAuth("", sa.username, sa.password, sa.host)
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(sa.from); err != nil {
+		return err
+	}
+
+	for _, to := range sa.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// Function for creating SMTP appender.
+// Supported configuration keys are:
+// address - address of SMTP server (default 127.0.0.1:25)
+// username, password - credentials for PLAIN authentication (optional)
+// from - sender address (default golog@<hostname>)
+// to - comma separated recipient addresses
+// subject - prefix of email subject (default golog)
+// level - min level of logs which are sent (default ERROR)
+// window - time during which logs are collected in one email (default 1m)
+// max_emails - max number of emails in rate period, 0 for no limit (default 10)
+// rate_period - period in which number of emails is limited (default 1h)
+// max_logs - max number of logs in one email (default 100)
+// starttls - use STARTTLS if server supports it (default true)
+// tls - use TLS connection from start, usually on port 465 (default false)
+// tls_ca, tls_server_name, tls_skip_verify - TLS configuration, the same as in network appender
+// timeout - timeout of sending one email (default 30s)
+func SMTP(cnf golog.Conf) *SMTPAppender {
+	address := confString(cnf, "address", "127.0.0.1:25")
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	// TLS configuration is needed for STARTTLS too
+	tlsCnf := golog.Conf{}
+	for k, v := range cnf {
+		tlsCnf[k] = v
+	}
+
+	tlsCnf["tls"] = "true"
+	config, err := tlsConf(tlsCnf)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+
+	if len(config.ServerName) == 0 {
+		config.ServerName = host
+	}
+
+	hostname, _ := os.Hostname()
+
+	to := []string{}
+	for _, addr := range strings.Split(cnf["to"], ",") {
+		if addr = strings.TrimSpace(addr); len(addr) > 0 {
+			to = append(to, addr)
+		}
+	}
+
+	maxLogs := confInt(cnf, "max_logs", 100)
+	if maxLogs <= 0 {
+		maxLogs = 100
+	}
+
+	return &SMTPAppender{
+		address:    address,
+		host:       host,
+		username:   cnf["username"],
+		password:   cnf["password"],
+		from:       confString(cnf, "from", "golog@"+hostname),
+		to:         to,
+		subject:    confString(cnf, "subject", "golog"),
+		level:      confLevel(cnf, "level", golog.ERROR),
+		window:     confDuration(cnf, "window", time.Minute),
+		maxEmails:  confInt(cnf, "max_emails", 10),
+		ratePeriod: confDuration(cnf, "rate_period", time.Hour),
+		maxLogs:    maxLogs,
+		useTLS:     confBool(cnf, "tls", false),
+		startTLS:   confBool(cnf, "starttls", true),
+		tls:        config,
+		timeout:    confDuration(cnf, "timeout", 30*time.Second),
+	}
+}
@@ -0,0 +1,270 @@
+package appenders
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Email received by fake SMTP server
+type smtpMail struct {
+	from string
+	to   []string
+	auth string
+	tls  bool
+	data string
+}
+
+// Fake SMTP server, which supports STARTTLS if TLS configuration is set, and PLAIN authentication.
+type smtpServer struct {
+	ln  net.Listener
+	tls *tls.Config
+
+	mu    sync.Mutex
+	mails []smtpMail
+}
+
+func newSMTPServer(t *testing.T, config *tls.Config) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	s := &smtpServer{ln: ln, tls: config}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.handle(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	mail := smtpMail{}
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO":
+			reply("250-localhost")
+			if s.tls != nil && !mail.tls {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			mail.tls = true
+		case "AUTH":
+			decoded, _ := base64.StdEncoding.DecodeString(strings.Fields(line)[2])
+			mail.auth = string(decoded)
+			reply("235 authenticated")
+		case "MAIL":
+			mail.from = line[len("MAIL FROM:"):]
+			reply("250 ok")
+		case "RCPT":
+			mail.to = append(mail.to, line[len("RCPT TO:"):])
+			reply("250 ok")
+		case "DATA":
+			reply("354 send data")
+
+			data := &strings.Builder{}
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if l == ".\r\n" {
+					break
+				}
+
+				data.WriteString(l)
+			}
+
+			mail.data = data.String()
+
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpServer) received() []smtpMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]smtpMail{}, s.mails...)
+}
+
+func TestSMTPId(t *testing.T) {
+	appender := SMTP(golog.Conf{})
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/smtp", appender.Id())
+}
+
+func TestSMTPDigest(t *testing.T) {
+	srv := newSMTPServer(t, nil)
+	defer srv.ln.Close()
+
+	appender := SMTP(golog.Conf{
+		"address": srv.ln.Addr().String(),
+		"from":    "app@example.com",
+		"to":      "oncall@example.com, boss@example.com",
+		"subject": "myapp",
+		"window":  "50ms",
+	})
+	defer appender.Close()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	ctx := golog.Ctx{"key": "value"}
+	appender.Append(golog.Log{Message: "not sent", Level: golog.WARN})
+	appender.Append(golog.Log{
+		Message: "first error",
+		Level:   golog.ERROR,
+		Time:    now,
+		Logger:  &golog.Logger{Name: "somelogger"},
+		Ctx:     ctx,
+		Data:    []interface{}{1, "two"},
+	})
+
+	// context is copied, so logger can change it
+	ctx["key"] = "changed"
+	appender.Append(golog.Log{Message: ".second\nline", Level: golog.PANIC, Time: now})
+
+	// digest is sent after window passes
+	assert.Len(t, srv.received(), 0)
+	time.Sleep(300 * time.Millisecond)
+
+	mails := srv.received()
+	assert.Len(t, mails, 1)
+	assert.Equal(t, "<app@example.com>", mails[0].from)
+	assert.Equal(t, []string{"<oncall@example.com>", "<boss@example.com>"}, mails[0].to)
+
+	data := mails[0].data
+	assert.Contains(t, data, "Subject: myapp: 2 logs\r\n")
+	assert.Contains(t, data, "2026-10-18T12:00:00Z ERROR somelogger\r\nfirst error\r\n  key: value\r\n  data: [1,\"two\"]\r\n")
+	assert.Contains(t, data, "2026-10-18T12:00:00Z PANIC\r\n..second\r\nline\r\n")
+	assert.NotContains(t, data, "not sent")
+}
+
+func TestSMTPRateLimit(t *testing.T) {
+	srv := newSMTPServer(t, nil)
+	defer srv.ln.Close()
+
+	appender := SMTP(golog.Conf{
+		"address":     srv.ln.Addr().String(),
+		"to":          "oncall@example.com",
+		"window":      "10ms",
+		"max_emails":  "1",
+		"rate_period": "300ms",
+		"max_logs":    "2",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first", Level: golog.ERROR})
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, srv.received(), 1)
+
+	// next email is sent after rate period, with logs collected meanwhile
+	for i := 0; i < 5; i++ {
+		appender.Append(golog.Log{Message: "next", Level: golog.ERROR})
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	assert.Len(t, srv.received(), 1)
+	time.Sleep(400 * time.Millisecond)
+
+	mails := srv.received()
+	assert.Len(t, mails, 2)
+	assert.Contains(t, mails[1].data, "Subject: golog: 5 logs\r\n")
+	assert.Contains(t, mails[1].data, "3 more logs are not included")
+}
+
+func TestSMTPStartTLS(t *testing.T) {
+	https := httptest.NewUnstartedServer(nil)
+	https.StartTLS()
+	certs := https.TLS.Certificates
+	https.Close()
+
+	srv := newSMTPServer(t, &tls.Config{Certificates: certs})
+	defer srv.ln.Close()
+
+	appender := SMTP(golog.Conf{
+		"address":         srv.ln.Addr().String(),
+		"to":              "oncall@example.com",
+		"username":        "user",
+		"password":        "secret",
+		"tls_skip_verify": "true",
+		"level":           "warn",
+	})
+
+	appender.Append(golog.Log{Message: "some warning", Level: golog.WARN})
+	assert.Nil(t, appender.Close())
+
+	mails := srv.received()
+	assert.Len(t, mails, 1)
+	assert.True(t, mails[0].tls)
+	assert.Equal(t, "\x00user\x00secret", mails[0].auth)
+
+	// logs are not collected after appender is closed
+	appender.Append(golog.Log{Message: "some warning", Level: golog.WARN})
+	assert.Nil(t, appender.Flush())
+	assert.Len(t, srv.received(), 1)
+}
+
+func TestSMTPError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	appender := SMTP(golog.Conf{
+		"address": address,
+		"to":      "oncall@example.com",
+	})
+
+	appender.Append(golog.Log{Message: "some error", Level: golog.ERROR})
+	assert.NotNil(t, appender.Close())
+}
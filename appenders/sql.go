@@ -0,0 +1,417 @@
+package appenders
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+)
+
+// Columns in which logs are inserted
+var sqlColumns = []string{"logged_at", "level", "level_value", "logger", "message", "pid", "ctx", "data"}
+
+// Table definitions for supported dialects.
+// {table} is replaced with table name, and {index} with name of index.
+var sqlSchemas = map[string][]string{
+	DialectPostgres: {
+		`CREATE TABLE IF NOT EXISTS {table} (
+			id BIGSERIAL PRIMARY KEY,
+			logged_at TIMESTAMPTZ NOT NULL,
+			level VARCHAR(16) NOT NULL,
+			level_value INTEGER NOT NULL,
+			logger VARCHAR(255) NOT NULL,
+			message TEXT NOT NULL,
+			pid INTEGER NOT NULL,
+			ctx JSONB,
+			data JSONB
+		)`,
+		`CREATE INDEX IF NOT EXISTS {index} ON {table} (logged_at)`,
+	},
+	DialectMySQL: {
+		`CREATE TABLE IF NOT EXISTS {table} (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			logged_at DATETIME(6) NOT NULL,
+			level VARCHAR(16) NOT NULL,
+			level_value INT NOT NULL,
+			logger VARCHAR(255) NOT NULL,
+			message TEXT NOT NULL,
+			pid INT NOT NULL,
+			ctx JSON,
+			data JSON,
+			INDEX logged_at_idx (logged_at)
+		)`,
+	},
+	DialectSQLite: {
+		`CREATE TABLE IF NOT EXISTS {table} (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			logged_at TIMESTAMP NOT NULL,
+			level TEXT NOT NULL,
+			level_value INTEGER NOT NULL,
+			logger TEXT NOT NULL,
+			message TEXT NOT NULL,
+			pid INTEGER NOT NULL,
+			ctx TEXT,
+			data TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS {index} ON {table} (logged_at)`,
+	},
+}
+
+var sqlIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// Representing appender which inserts logs in SQL database using database/sql.
+// Database driver is supplied by caller. Logs are collected in batches,
+// and inserted from background goroutine using multi-row inserts, or in transaction.
+// Context and data are stored as JSON.
+//
+// If retention is configured, rows older than retention period are deleted periodically.
+type SQLAppender struct {
+	db            *sql.DB
+	dialect       string
+	table         string
+	createTable   bool
+	useTx         bool
+	rowsPerInsert int
+	retention     time.Duration
+	pruneInterval time.Duration
+	batcher       *batcher
+
+	mu          sync.Mutex
+	tableReady  bool
+	lastPruning time.Time
+}
+
+// github.com/ivpusic/golog/appenders/sql
+func (sa *SQLAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/sql"
+}
+
+func (sa *SQLAppender) Append(log golog.Log) {
+	if err := sa.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Will add log to current batch.
+func (sa *SQLAppender) TryAppend(log golog.Log) error {
+	return sa.batcher.add(log)
+}
+
+// Inserting logs. Table is created before first insert if it is enabled,
+// and old rows are deleted if it is time for that.
+func (sa *SQLAppender) AppendBatch(logs []golog.Log) error {
+	if err := sa.CreateTable(); err != nil {
+		return err
+	}
+
+	var err error
+	if sa.useTx {
+		err = sa.insertTx(logs)
+	} else {
+		err = sa.insertMultirow(logs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if sa.retention > 0 && sa.pruningDue() {
+		if err := sa.Prune(); err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Will insert logs using multi-row inserts.
+// Every statement has at most insert_rows rows, to keep number of parameters in limits of databases.
+// If more statements are needed, they are executed in transaction,
+// so batch which is reported as failed is never partially inserted.
+func (sa *SQLAppender) insertMultirow(logs []golog.Log) error {
+	var tx *sql.Tx
+	exec := sa.db.Exec
+	if len(logs) > sa.rowsPerInsert {
+		var err error
+		if tx, err = sa.db.Begin(); err != nil {
+			return err
+		}
+
+		exec = tx.Exec
+	}
+
+	for start := 0; start < len(logs); start += sa.rowsPerInsert {
+		end := start + sa.rowsPerInsert
+		if end > len(logs) {
+			end = len(logs)
+		}
+
+		args := make([]interface{}, 0, (end-start)*len(sqlColumns))
+		for _, log := range logs[start:end] {
+			values, err := sqlValues(log)
+			if err != nil {
+				if tx != nil {
+					tx.Rollback()
+				}
+
+				return err
+			}
+
+			args = append(args, values...)
+		}
+
+		if _, err := exec(sa.insertQuery(end-start), args...); err != nil {
+			if tx != nil {
+				tx.Rollback()
+			}
+
+			return err
+		}
+	}
+
+	if tx != nil {
+		return tx.Commit()
+	}
+
+	return nil
+}
+
+// Will insert logs one by one using prepared statement in transaction.
+func (sa *SQLAppender) insertTx(logs []golog.Log) error {
+	tx, err := sa.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(sa.insertQuery(1))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	defer stmt.Close()
+
+	for _, log := range logs {
+		values, err := sqlValues(log)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if _, err := stmt.Exec(values...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Will make insert statement with provided number of rows.
+func (sa *SQLAppender) insertQuery(rows int) string {
+	query := &strings.Builder{}
+	query.WriteString("INSERT INTO " + sa.table + " (" + strings.Join(sqlColumns, ", ") + ") VALUES ")
+
+	n := 0
+	for row := 0; row < rows; row++ {
+		if row > 0 {
+			query.WriteString(", ")
+		}
+
+		query.WriteString("(")
+		for i := range sqlColumns {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+
+			n++
+			query.WriteString(sa.placeholder(n))
+		}
+
+		query.WriteString(")")
+	}
+
+	return query.String()
+}
+
+// Will return placeholder of n-th parameter, starting from 1.
+func (sa *SQLAppender) placeholder(n int) string {
+	if sa.dialect == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+
+	return "?"
+}
+
+// Will create table and index if they don't exist.
+// It is called before first insert, and it does nothing if creating table is disabled.
+func (sa *SQLAppender) CreateTable() error {
+	if !sa.createTable {
+		return nil
+	}
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if sa.tableReady {
+		return nil
+	}
+
+	// index name cannot contain schema name
+	index := sa.table[strings.LastIndex(sa.table, ".")+1:] + "_logged_at_idx"
+	replacer := strings.NewReplacer("{table}", sa.table, "{index}", index)
+
+	for _, schema := range sqlSchemas[sa.dialect] {
+		if _, err := sa.db.Exec(replacer.Replace(schema)); err != nil {
+			return err
+		}
+	}
+
+	sa.tableReady = true
+	return nil
+}
+
+// Will delete rows older than retention period.
+func (sa *SQLAppender) Prune() error {
+	if sa.retention <= 0 {
+		return nil
+	}
+
+	query := "DELETE FROM " + sa.table + " WHERE logged_at < " + sa.placeholder(1)
+	_, err := sa.db.Exec(query, time.Now().Add(-sa.retention).UTC())
+	return err
+}
+
+func (sa *SQLAppender) pruningDue() bool {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if time.Since(sa.lastPruning) < sa.pruneInterval {
+		return false
+	}
+
+	sa.lastPruning = time.Now()
+	return true
+}
+
+// Will set function which is called when batch of logs cannot be inserted.
+// By default errors are printed.
+func (sa *SQLAppender) OnError(fn func(err error, logs []golog.Log)) *SQLAppender {
+	sa.batcher.setErrorHandler(fn)
+	return sa
+}
+
+func (sa *SQLAppender) onDeliveryError(fn func(error, []golog.Log)) {
+	sa.batcher.watchers.add(fn)
+}
+
+// Will insert current batch, and wait until all pending batches are inserted.
+func (sa *SQLAppender) Flush() error {
+	return sa.batcher.flush()
+}
+
+// Will insert all collected logs and stop appender.
+// Database is not closed, because it is owned by caller.
+func (sa *SQLAppender) Close() error {
+	return sa.batcher.close()
+}
+
+// Will return values of columns for log.
+func sqlValues(log golog.Log) ([]interface{}, error) {
+	timestamp := log.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	var ctx, data interface{}
+	if len(log.Ctx) > 0 {
+		encoded, err := json.Marshal(log.Ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = string(encoded)
+	}
+
+	if len(log.Data) > 0 {
+		encoded, err := json.Marshal(log.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		data = string(encoded)
+	}
+
+	return []interface{}{
+		timestamp.UTC(),
+		log.Level.Name,
+		log.Level.Value,
+		loggerName(log),
+		log.Message,
+		log.Pid,
+		ctx,
+		data,
+	}, nil
+}
+
+// Function for creating SQL appender. Database driver has to be imported by caller.
+// Supported configuration keys are:
+// dialect - postgres, mysql or sqlite (default postgres)
+// table - name of table, optionally with schema (default logs)
+// create_table - create table if it doesn't exist (default true)
+// mode - multirow or tx, insert logs using multi-row inserts or in transaction (default multirow)
+// insert_rows - max number of rows in one multi-row insert (default 100)
+// retention - delete logs older than this duration, for example 720h (default disabled)
+// prune_interval - time between deleting old logs (default 1h)
+// Batching is configured using the same keys as in HTTP appender.
+func SQL(db *sql.DB, cnf golog.Conf) *SQLAppender {
+	dialect := confString(cnf, "dialect", DialectPostgres)
+	if _, ok := sqlSchemas[dialect]; !ok {
+		fmt.Println("unknown sql dialect " + dialect + ", using postgres")
+		dialect = DialectPostgres
+	}
+
+	table := confString(cnf, "table", "logs")
+	if !sqlIdentifier.MatchString(table) {
+		fmt.Println("invalid sql table name " + table + ", using logs")
+		table = "logs"
+	}
+
+	mode := confString(cnf, "mode", "multirow")
+	if mode != "multirow" && mode != "tx" {
+		fmt.Println("unknown sql mode " + mode + ", using multirow")
+		mode = "multirow"
+	}
+
+	insertRows := confInt(cnf, "insert_rows", 100)
+	if insertRows <= 0 {
+		insertRows = 100
+	}
+
+	sa := &SQLAppender{
+		db:            db,
+		dialect:       dialect,
+		table:         table,
+		createTable:   confBool(cnf, "create_table", true),
+		useTx:         mode == "tx",
+		rowsPerInsert: insertRows,
+		retention:     confDuration(cnf, "retention", 0),
+		pruneInterval: confDuration(cnf, "prune_interval", time.Hour),
+	}
+
+	sa.batcher = newBatcher(cnf, sa.AppendBatch)
+
+	return sa
+}
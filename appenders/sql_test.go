@@ -0,0 +1,275 @@
+package appenders
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Statement executed by fake database driver
+type sqlExec struct {
+	query string
+	args  []driver.Value
+	tx    bool
+}
+
+// Fake database driver, which remembers executed statements.
+// Statements are failed if they contain fail string.
+type sqlFakeDriver struct {
+	mu    sync.Mutex
+	execs []sqlExec
+	fail  string
+}
+
+func (d *sqlFakeDriver) Open(name string) (driver.Conn, error) {
+	return &sqlFakeConn{d: d}, nil
+}
+
+func (d *sqlFakeDriver) executed() []sqlExec {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]sqlExec{}, d.execs...)
+}
+
+type sqlFakeConn struct {
+	d  *sqlFakeDriver
+	tx bool
+}
+
+func (c *sqlFakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &sqlFakeStmt{c, query}, nil
+}
+
+func (c *sqlFakeConn) Close() error {
+	return nil
+}
+
+func (c *sqlFakeConn) Begin() (driver.Tx, error) {
+	c.tx = true
+	return c, nil
+}
+
+func (c *sqlFakeConn) Commit() error {
+	c.tx = false
+	return nil
+}
+
+func (c *sqlFakeConn) Rollback() error {
+	c.tx = false
+	return nil
+}
+
+type sqlFakeStmt struct {
+	c     *sqlFakeConn
+	query string
+}
+
+func (s *sqlFakeStmt) Close() error {
+	return nil
+}
+
+func (s *sqlFakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *sqlFakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.fail) > 0 && strings.Contains(s.query, d.fail) {
+		return nil, errors.New("statement failed")
+	}
+
+	d.execs = append(d.execs, sqlExec{s.query, args, s.c.tx})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *sqlFakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+var sqlDriverMu sync.Mutex
+var sqlDriverCount int
+
+// Will open database using new instance of fake driver.
+func openFakeDB(t *testing.T) (*sql.DB, *sqlFakeDriver) {
+	sqlDriverMu.Lock()
+	sqlDriverCount++
+	name := "golog-fake-" + strconv.Itoa(sqlDriverCount)
+	sqlDriverMu.Unlock()
+
+	d := &sqlFakeDriver{}
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	assert.Nil(t, err)
+
+	// the same connection is used for all statements
+	db.SetMaxOpenConns(1)
+	return db, d
+}
+
+func TestSQLId(t *testing.T) {
+	db, _ := openFakeDB(t)
+	appender := SQL(db, golog.Conf{})
+	defer appender.Close()
+
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/sql", appender.Id())
+}
+
+func TestSQLMultirow(t *testing.T) {
+	db, d := openFakeDB(t)
+	appender := SQL(db, golog.Conf{
+		"insert_rows":    "2",
+		"flush_interval": "1h",
+	})
+	defer appender.Close()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	appender.Append(golog.Log{
+		Message: "first",
+		Level:   golog.INFO,
+		Time:    now,
+		Pid:     42,
+		Logger:  &golog.Logger{Name: "somelogger"},
+		Ctx:     golog.Ctx{"key": "value"},
+		Data:    []interface{}{1},
+	})
+	appender.Append(golog.Log{Message: "second", Time: now})
+	appender.Append(golog.Log{Message: "third", Time: now})
+	assert.Nil(t, appender.Flush())
+
+	execs := d.executed()
+	assert.Len(t, execs, 4)
+
+	// table and index are created first
+	assert.True(t, strings.HasPrefix(execs[0].query, "CREATE TABLE IF NOT EXISTS logs ("))
+	assert.Equal(t, "CREATE INDEX IF NOT EXISTS logs_logged_at_idx ON logs (logged_at)", execs[1].query)
+
+	assert.Equal(t, "INSERT INTO logs (logged_at, level, level_value, logger, message, pid, ctx, data) VALUES "+
+		"($1, $2, $3, $4, $5, $6, $7, $8), ($9, $10, $11, $12, $13, $14, $15, $16)", execs[2].query)
+	assert.Equal(t, []driver.Value{now, "INFO", int64(20), "somelogger", "first", int64(42), `{"key":"value"}`, "[1]"}, execs[2].args[:8])
+	assert.Nil(t, execs[2].args[14])
+
+	assert.Len(t, execs[3].args, 8)
+	assert.Equal(t, "third", execs[3].args[4])
+
+	// statements of one batch are executed in transaction
+	assert.True(t, execs[2].tx)
+	assert.True(t, execs[3].tx)
+
+	// table is created only once, and transaction is not needed for one statement
+	appender.Append(golog.Log{Message: "fourth"})
+	assert.Nil(t, appender.Flush())
+	execs = d.executed()
+	assert.Len(t, execs, 5)
+	assert.False(t, execs[4].tx)
+}
+
+func TestSQLTransaction(t *testing.T) {
+	db, d := openFakeDB(t)
+	appender := SQL(db, golog.Conf{
+		"dialect":      "mysql",
+		"table":        "app.logs",
+		"mode":         "tx",
+		"create_table": "false",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Flush())
+
+	execs := d.executed()
+	assert.Len(t, execs, 2)
+
+	for _, exec := range execs {
+		assert.Equal(t, "INSERT INTO app.logs (logged_at, level, level_value, logger, message, pid, ctx, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", exec.query)
+		assert.True(t, exec.tx)
+	}
+}
+
+func TestSQLSchemas(t *testing.T) {
+	for _, dialect := range []string{DialectMySQL, DialectSQLite} {
+		db, d := openFakeDB(t)
+		appender := SQL(db, golog.Conf{
+			"dialect": dialect,
+			"table":   "app.logs",
+		})
+
+		assert.Nil(t, appender.CreateTable())
+		for _, exec := range d.executed() {
+			assert.Contains(t, exec.query, "app.logs")
+			assert.NotContains(t, exec.query, "{")
+		}
+
+		appender.Close()
+	}
+}
+
+func TestSQLInvalidTable(t *testing.T) {
+	db, d := openFakeDB(t)
+	appender := SQL(db, golog.Conf{
+		"table":        "logs; DROP TABLE users",
+		"create_table": "false",
+	})
+	defer appender.Close()
+
+	assert.Nil(t, appender.AppendBatch([]golog.Log{{Message: "first"}}))
+	assert.True(t, strings.HasPrefix(d.executed()[0].query, "INSERT INTO logs ("))
+}
+
+func TestSQLRetention(t *testing.T) {
+	db, d := openFakeDB(t)
+	appender := SQL(db, golog.Conf{
+		"create_table": "false",
+		"retention":    "24h",
+	})
+	defer appender.Close()
+
+	assert.Nil(t, appender.AppendBatch([]golog.Log{{Message: "first"}}))
+	assert.Nil(t, appender.AppendBatch([]golog.Log{{Message: "second"}}))
+
+	// old rows are deleted after first batch, and then once per prune interval
+	execs := d.executed()
+	assert.Len(t, execs, 3)
+	assert.Equal(t, "DELETE FROM logs WHERE logged_at < $1", execs[1].query)
+
+	deadline := execs[1].args[0].(time.Time)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), deadline, time.Minute)
+}
+
+func TestSQLError(t *testing.T) {
+	db, d := openFakeDB(t)
+	d.fail = "CREATE TABLE"
+
+	appender := SQL(db, golog.Conf{})
+
+	var failed []golog.Log
+	appender.OnError(func(err error, logs []golog.Log) {
+		failed = append(failed, logs...)
+	})
+
+	appender.Append(golog.Log{Message: "first"})
+	appender.Flush()
+	assert.Len(t, failed, 1)
+
+	// creating table is tried again with next batch
+	d.mu.Lock()
+	d.fail = ""
+	d.mu.Unlock()
+
+	appender.Append(golog.Log{Message: "second"})
+	assert.Nil(t, appender.Close())
+	assert.Len(t, d.executed(), 3)
+}
@@ -0,0 +1,344 @@
+package appenders
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+const (
+	// syslog message formats
+	RFC5424 = "rfc5424"
+	RFC3164 = "rfc3164"
+
+	// id of structured data element which contains log context
+	// 32473 is private enterprise number reserved for documentation
+	syslogSDID = "ctx@32473"
+)
+
+var (
+	syslogFacilities = map[string]int{
+		"kern":     0,
+		"user":     1,
+		"mail":     2,
+		"daemon":   3,
+		"auth":     4,
+		"syslog":   5,
+		"lpr":      6,
+		"news":     7,
+		"uucp":     8,
+		"cron":     9,
+		"authpriv": 10,
+		"ftp":      11,
+		"local0":   16,
+		"local1":   17,
+		"local2":   18,
+		"local3":   19,
+		"local4":   20,
+		"local5":   21,
+		"local6":   22,
+		"local7":   23,
+	}
+
+	// paths where local syslog daemon is usually listening
+	syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+	errSyslogNotConnected = errors.New("syslog: not connected, waiting before next reconnect")
+)
+
+// Will return syslog severity for golog level.
+func syslogSeverity(lvl golog.Level) int {
+	switch {
+	case lvl.Value >= golog.PANIC.Value:
+		// critical
+		return 2
+	case lvl.Value >= golog.ERROR.Value:
+		// error
+		return 3
+	case lvl.Value >= golog.WARN.Value:
+		// warning
+		return 4
+	case lvl.Value >= golog.INFO.Value:
+		// informational
+		return 6
+	default:
+		// debug
+		return 7
+	}
+}
+
+// Representing appender which sends logs to syslog server.
+// Messages can be formatted according to RFC 5424 or RFC 3164.
+// Over tcp connections messages are framed using octet counting.
+// Over unix sockets every message is sent using one write, without framing,
+// as local syslog daemons expect.
+type SyslogAppender struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	stream  bool
+	backoff backoff
+	retryAt time.Time
+
+	network  string
+	address  string
+	facility int
+	hostname string
+	app      string
+	format   string
+	timeout  time.Duration
+}
+
+// github.com/ivpusic/golog/appenders/syslog
+func (sa *SyslogAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/syslog"
+}
+
+func (sa *SyslogAppender) Append(log golog.Log) {
+	if err := sa.TryAppend(log); err != nil {
+		reportError(log, err)
+	}
+}
+
+// Sending log to syslog server, and returning error if log cannot be sent.
+// If connection is broken, appender will reconnect and try again once.
+func (sa *SyslogAppender) TryAppend(log golog.Log) error {
+	msg := sa.Format(log)
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	connected := sa.conn != nil
+	err := sa.write(msg)
+	if err != nil && connected {
+		sa.close()
+		err = sa.write(msg)
+	}
+
+	return err
+}
+
+func (sa *SyslogAppender) write(msg string) error {
+	if sa.conn == nil {
+		if err := sa.connect(); err != nil {
+			return err
+		}
+	}
+
+	if sa.stream {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+
+	if sa.timeout > 0 {
+		sa.conn.SetWriteDeadline(time.Now().Add(sa.timeout))
+	}
+
+	_, err := sa.conn.Write([]byte(msg))
+	if err != nil {
+		sa.close()
+	}
+
+	return err
+}
+
+// Will connect to syslog server. After failed attempt,
+// next attempt is made after exponential backoff.
+func (sa *SyslogAppender) connect() error {
+	if time.Now().Before(sa.retryAt) {
+		return errSyslogNotConnected
+	}
+
+	conn, err := sa.dial()
+	if err != nil {
+		sa.retryAt = time.Now().Add(sa.backoff.next())
+		return err
+	}
+
+	sa.backoff.reset()
+	sa.conn = conn
+
+	switch conn.LocalAddr().Network() {
+	case "tcp", "tcp4", "tcp6":
+		sa.stream = true
+	default:
+		sa.stream = false
+	}
+
+	return nil
+}
+
+func (sa *SyslogAppender) dial() (net.Conn, error) {
+	if sa.network != "unix" {
+		return net.DialTimeout(sa.network, sa.address, sa.timeout)
+	}
+
+	// local syslog daemon can listen on datagram or stream socket
+	addresses := syslogLocalPaths
+	if len(sa.address) > 0 {
+		addresses = []string{sa.address}
+	}
+
+	var err error
+	for _, address := range addresses {
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			if conn, err = net.DialTimeout(network, address, sa.timeout); err == nil {
+				return conn, nil
+			}
+		}
+	}
+
+	return nil, err
+}
+
+func (sa *SyslogAppender) close() error {
+	if sa.conn == nil {
+		return nil
+	}
+
+	err := sa.conn.Close()
+	sa.conn = nil
+	return err
+}
+
+// Will close connection to syslog server.
+// Appender will connect again on next log.
+func (sa *SyslogAppender) Close() error {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	return sa.close()
+}
+
+// Formatting log as syslog message, without transport framing.
+func (sa *SyslogAppender) Format(log golog.Log) string {
+	pri := "<" + strconv.Itoa(sa.facility*8+syslogSeverity(log.Level)) + ">"
+	if sa.format == RFC3164 {
+		return sa.format3164(pri, log)
+	}
+
+	return sa.format5424(pri, log)
+}
+
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (sa *SyslogAppender) format5424(pri string, log golog.Log) string {
+	timestamp := "-"
+	if !log.Time.IsZero() {
+		timestamp = log.Time.Format("2006-01-02T15:04:05.999999Z07:00")
+	}
+
+	sd := "-"
+	if len(log.Ctx) > 0 {
+		sd = "[" + syslogSDID
+		for _, k := range sortedKeys(log.Ctx) {
+			sd += " " + syslogName(k, 32, "_") + "=\"" + syslogEscape(fmt.Sprint(log.Ctx[k])) + "\""
+		}
+
+		sd += "]"
+	}
+
+	return strings.Join([]string{
+		pri + "1",
+		timestamp,
+		syslogName(sa.hostname, 255, "-"),
+		syslogName(sa.app, 48, "-"),
+		strconv.Itoa(log.Pid),
+		syslogName(loggerName(log), 32, "-"),
+		sd,
+		log.Message,
+	}, " ")
+}
+
+// <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+// RFC 3164 has no structured data, so context is appended to message.
+func (sa *SyslogAppender) format3164(pri string, log golog.Log) string {
+	timestamp := log.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	msg := log.Message
+	for _, k := range sortedKeys(log.Ctx) {
+		msg += " " + k + "=" + fmt.Sprint(log.Ctx[k])
+	}
+
+	return pri + timestamp.Local().Format(time.Stamp) + " " +
+		syslogName(sa.hostname, 255, "-") + " " +
+		syslogName(sa.app, 32, "golog") + "[" + strconv.Itoa(log.Pid) + "]: " + msg
+}
+
+// Will make valid syslog header field or structured data name.
+// Only printable ascii characters are allowed, other characters are replaced with underscore.
+func syslogName(name string, max int, empty string) string {
+	if len(name) == 0 {
+		return empty
+	}
+
+	b := []byte(name)
+	for i, c := range b {
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+
+	if len(b) > max {
+		b = b[:max]
+	}
+
+	return string(b)
+}
+
+// Escaping structured data parameter value.
+func syslogEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// Function for creating syslog appender.
+// Connection is made on first log, so this function never fails.
+// Supported configuration keys are:
+// network - udp, tcp or unix (default udp, or unix if address is not set)
+// address - address of syslog server, or path of unix socket
+// facility - syslog facility name or number (default user)
+// app - application name (default name of executable)
+// hostname - host name sent in messages (default host name of machine)
+// format - rfc5424 or rfc3164 (default rfc5424)
+// timeout - timeout for connecting and writing (default 5s)
+func Syslog(cnf golog.Conf) *SyslogAppender {
+	network := confString(cnf, "network", "udp")
+	if len(cnf["network"]) == 0 && len(cnf["address"]) == 0 {
+		network = "unix"
+	}
+
+	facility, ok := syslogFacilities[cnf["facility"]]
+	if !ok {
+		facility = confInt(cnf, "facility", 1)
+	}
+
+	hostname, _ := os.Hostname()
+
+	format := strings.ToLower(confString(cnf, "format", RFC5424))
+	if format != RFC3164 {
+		format = RFC5424
+	}
+
+	return &SyslogAppender{
+		network:  network,
+		address:  cnf["address"],
+		facility: facility,
+		hostname: confString(cnf, "hostname", hostname),
+		app:      confString(cnf, "app", filepath.Base(os.Args[0])),
+		format:   format,
+		timeout:  confDuration(cnf, "timeout", 5*time.Second),
+		backoff: backoff{
+			min: 100 * time.Millisecond,
+			max: 30 * time.Second,
+		},
+	}
+}
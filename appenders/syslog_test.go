@@ -0,0 +1,214 @@
+package appenders
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func readOctetCounted(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, n)
+	_, err = io.ReadFull(r, msg)
+	return string(msg), err
+}
+
+func TestSyslogId(t *testing.T) {
+	appender := Syslog(golog.Conf{})
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/syslog", appender.Id())
+}
+
+func TestSyslogFormat5424(t *testing.T) {
+	appender := Syslog(golog.Conf{
+		"network":  "udp",
+		"address":  "127.0.0.1:514",
+		"facility": "local0",
+		"hostname": "somehost",
+		"app":      "someapp",
+	})
+
+	log := golog.Log{
+		Time:    time.Date(2026, 10, 18, 12, 30, 15, 123000000, time.UTC),
+		Message: "some message",
+		Level:   golog.ERROR,
+		Pid:     123,
+		Logger:  golog.GetLogger("syslog"),
+		Ctx: golog.Ctx{
+			"user":  "some\"one",
+			"count": 1,
+		},
+	}
+
+	assert.Equal(t, `<131>1 2026-10-18T12:30:15.123Z somehost someapp 123 syslog `+
+		`[ctx@32473 count="1" user="some\"one"] some message`, appender.Format(log))
+
+	log.Ctx = nil
+	log.Level = golog.DEBUG
+	assert.Equal(t, `<135>1 2026-10-18T12:30:15.123Z somehost someapp 123 syslog - some message`,
+		appender.Format(log))
+}
+
+func TestSyslogFormat3164(t *testing.T) {
+	appender := Syslog(golog.Conf{
+		"network":  "udp",
+		"address":  "127.0.0.1:514",
+		"hostname": "somehost",
+		"app":      "someapp",
+		"format":   "rfc3164",
+	})
+
+	log := golog.Log{
+		Time:    time.Date(2026, 10, 8, 12, 30, 15, 0, time.Local),
+		Message: "some message",
+		Level:   golog.WARN,
+		Pid:     123,
+		Ctx:     golog.Ctx{"user": "someone"},
+	}
+
+	assert.Equal(t, "<12>Oct  8 12:30:15 somehost someapp[123]: some message user=someone", appender.Format(log))
+}
+
+func TestSyslogLevels(t *testing.T) {
+	assert.Exactly(t, 7, syslogSeverity(golog.DEBUG))
+	assert.Exactly(t, 6, syslogSeverity(golog.INFO))
+	assert.Exactly(t, 4, syslogSeverity(golog.WARN))
+	assert.Exactly(t, 3, syslogSeverity(golog.ERROR))
+	assert.Exactly(t, 2, syslogSeverity(golog.PANIC))
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	appender := Syslog(golog.Conf{
+		"network": "udp",
+		"address": conn.LocalAddr().String(),
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "some message", Level: golog.INFO})
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<14>1 "))
+	assert.True(t, strings.HasSuffix(msg, " - - some message"))
+}
+
+func TestSyslogTCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	appender := Syslog(golog.Conf{
+		"network": "tcp",
+		"address": ln.Addr().String(),
+	})
+	defer appender.Close()
+
+	received := make(chan string, 100)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			// every connection receives only one message,
+			// so appender has to reconnect
+			msg, err := readOctetCounted(bufio.NewReader(conn))
+			if err == nil {
+				received <- msg
+			}
+
+			conn.Close()
+		}
+	}()
+
+	appender.Append(golog.Log{Message: "first message"})
+	msg := <-received
+	assert.True(t, strings.HasSuffix(msg, " first message"))
+
+	timeout := time.After(5 * time.Second)
+	for {
+		appender.TryAppend(golog.Log{Message: "second message"})
+
+		select {
+		case msg = <-received:
+			assert.True(t, strings.HasSuffix(msg, " second message"))
+			return
+		case <-timeout:
+			t.Fatal("second message is not received")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestSyslogUnixStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip("unix sockets are not supported")
+	}
+	defer ln.Close()
+
+	appender := Syslog(golog.Conf{
+		"network": "unix",
+		"address": path,
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "some message", Level: golog.INFO})
+
+	conn, err := ln.Accept()
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// message is not prefixed with its length
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	assert.Nil(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<14>1 "))
+	assert.True(t, strings.HasSuffix(msg, " - - some message"))
+}
+
+func TestSyslogUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	appender := Syslog(golog.Conf{
+		"network": "tcp",
+		"address": address,
+	})
+
+	assert.NotNil(t, appender.TryAppend(golog.Log{Message: "some message"}))
+
+	// next connection attempt is delayed
+	assert.Equal(t, errSyslogNotConnected, appender.TryAppend(golog.Log{Message: "some message"}))
+}
@@ -0,0 +1,303 @@
+package appenders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ivpusic/golog"
+)
+
+// Default payload, which works with Slack and Mattermost incoming webhooks.
+const webhookTemplate = `{"text": {{json .Text}}}`
+
+// Data which is passed to payload template.
+// One message is made for every burst of logs.
+type WebhookMessage struct {
+	// Logs collected in one post
+	Logs []golog.Log
+
+	// Number of logs which are not included because of max_logs limit
+	Omitted int
+
+	// Number of logs which are dropped by throttling since previous post
+	Suppressed int
+}
+
+// Will return text with one line for every log.
+func (wm WebhookMessage) Text() string {
+	lines := []string{}
+	for _, log := range wm.Logs {
+		line := log.Level.Name
+		if name := loggerName(log); len(name) > 0 {
+			line += " " + name
+		}
+
+		lines = append(lines, line+": "+log.Message)
+	}
+
+	if wm.Omitted > 0 {
+		lines = append(lines, fmt.Sprintf("%d more logs are not included", wm.Omitted))
+	}
+
+	if wm.Suppressed > 0 {
+		lines = append(lines, fmt.Sprintf("%d logs are suppressed by throttling", wm.Suppressed))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Functions which can be used in payload template.
+var webhookFuncs = template.FuncMap{
+	// value encoded as JSON, useful for quoting strings
+	"json": func(value interface{}) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+	// name of logger without padding
+	"logger": loggerName,
+}
+
+// Representing appender which posts logs to incoming webhook of chat service,
+// like Slack, Mattermost or Microsoft Teams. Payload is made using text/template.
+//
+// Logs which arrive within window are grouped in one post. To avoid flooding channels,
+// the same message is posted at most once in message_throttle period,
+// and number of posted logs of one logger is limited in logger period.
+type WebhookAppender struct {
+	url          string
+	level        golog.Level
+	template     *template.Template
+	contentType  string
+	window       time.Duration
+	maxLogs      int
+	msgThrottle  time.Duration
+	loggerLimit  int
+	loggerPeriod time.Duration
+	sender       *httpSender
+
+	mu         sync.Mutex
+	logs       []golog.Log
+	omitted    int
+	suppressed int
+	timer      *time.Timer
+	messages   map[string]time.Time
+	loggers    map[string][]time.Time
+	closed     bool
+
+	// posting is serialized, and it is not done under lock
+	sendMu sync.Mutex
+}
+
+// github.com/ivpusic/golog/appenders/webhook
+func (wa *WebhookAppender) Id() string {
+	return "github.com/ivpusic/golog/appenders/webhook"
+}
+
+// Will add log to current burst, if it has required level and it is not throttled.
+// Burst is posted when window which started with first log passes.
+func (wa *WebhookAppender) Append(log golog.Log) {
+	if log.Level.Value < wa.level.Value {
+		return
+	}
+
+	wa.mu.Lock()
+	defer wa.mu.Unlock()
+
+	if wa.closed {
+		return
+	}
+
+	if wa.throttled(log, time.Now()) {
+		wa.suppressed++
+		return
+	}
+
+	if len(wa.logs) < wa.maxLogs {
+		// payload is made later from timer goroutine
+		wa.logs = append(wa.logs, detachLog(log))
+	} else {
+		wa.omitted++
+	}
+
+	if wa.timer == nil {
+		wa.timer = time.AfterFunc(wa.window, wa.windowEnd)
+	}
+}
+
+// Will check whether log should be dropped, and remember it if it is not.
+// Caller has to hold lock.
+func (wa *WebhookAppender) throttled(log golog.Log, now time.Time) bool {
+	name := loggerName(log)
+
+	var key string
+	if wa.msgThrottle > 0 {
+		key = name + "\x00" + log.Level.Name + "\x00" + log.Message
+		if last, ok := wa.messages[key]; ok && now.Sub(last) < wa.msgThrottle {
+			return true
+		}
+	}
+
+	if wa.loggerLimit > 0 {
+		recent := wa.loggers[name][:0]
+		for _, t := range wa.loggers[name] {
+			if now.Sub(t) < wa.loggerPeriod {
+				recent = append(recent, t)
+			}
+		}
+
+		if len(recent) >= wa.loggerLimit {
+			wa.loggers[name] = recent
+			return true
+		}
+
+		wa.loggers[name] = append(recent, now)
+	}
+
+	if wa.msgThrottle > 0 {
+		wa.messages[key] = now
+	}
+
+	return false
+}
+
+// Will forget throttling state which is not needed anymore, so maps don't grow forever.
+// Caller has to hold lock.
+func (wa *WebhookAppender) cleanup(now time.Time) {
+	for key, last := range wa.messages {
+		if now.Sub(last) >= wa.msgThrottle {
+			delete(wa.messages, key)
+		}
+	}
+
+	for name, times := range wa.loggers {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= wa.loggerPeriod {
+			delete(wa.loggers, name)
+		}
+	}
+}
+
+func (wa *WebhookAppender) windowEnd() {
+	wa.mu.Lock()
+	wa.timer = nil
+	wa.mu.Unlock()
+
+	if err := wa.Flush(); err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// Will post collected logs immediately.
+func (wa *WebhookAppender) Flush() error {
+	wa.sendMu.Lock()
+	defer wa.sendMu.Unlock()
+
+	wa.mu.Lock()
+	msg := WebhookMessage{Logs: wa.logs, Omitted: wa.omitted}
+	if len(msg.Logs) > 0 {
+		msg.Suppressed = wa.suppressed
+		wa.suppressed = 0
+	}
+
+	wa.logs, wa.omitted = nil, 0
+	if wa.timer != nil {
+		wa.timer.Stop()
+		wa.timer = nil
+	}
+
+	wa.cleanup(time.Now())
+	wa.mu.Unlock()
+
+	if len(msg.Logs) == 0 {
+		return nil
+	}
+
+	payload, err := wa.Payload(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = wa.sender.send("POST", wa.url, wa.contentType, payload)
+	return err
+}
+
+// Will post collected logs and stop appender.
+func (wa *WebhookAppender) Close() error {
+	err := wa.Flush()
+
+	wa.mu.Lock()
+	wa.closed = true
+	wa.mu.Unlock()
+
+	return err
+}
+
+// Will render payload template for message.
+func (wa *WebhookAppender) Payload(msg WebhookMessage) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := wa.template.Execute(buf, msg); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Function for creating webhook appender.
+// Supported configuration keys are:
+// url - URL of incoming webhook
+// level - min level of logs which are posted (default WARN)
+// template - text/template of payload, executed with WebhookMessage (default Slack compatible {"text": ...})
+// template_file - path of file with payload template, used if template is not set
+// content_type - content type of payload (default application/json)
+// window - time during which logs are grouped in one post (default 5s)
+// max_logs - max number of logs in one post (default 20)
+// message_throttle - the same message of logger is posted at most once in this period, 0 to disable (default 1m)
+// logger_limit - max number of posted logs of one logger in logger period, 0 to disable (default 30)
+// logger_period - period in which logs of one logger are limited (default 1m)
+// Retries, headers and TLS are configured using the same keys as in HTTP appender.
+func Webhook(cnf golog.Conf) *WebhookAppender {
+	text := cnf["template"]
+	if path := cnf["template_file"]; len(text) == 0 && len(path) > 0 {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+
+		text = string(content)
+	}
+
+	if len(text) == 0 {
+		text = webhookTemplate
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(text)
+	if err != nil {
+		fmt.Println(err.Error() + ", using default template")
+		tmpl = template.Must(template.New("webhook").Funcs(webhookFuncs).Parse(webhookTemplate))
+	}
+
+	maxLogs := confInt(cnf, "max_logs", 20)
+	if maxLogs <= 0 {
+		maxLogs = 20
+	}
+
+	return &WebhookAppender{
+		url:          cnf["url"],
+		level:        confLevel(cnf, "level", golog.WARN),
+		template:     tmpl,
+		contentType:  confString(cnf, "content_type", "application/json"),
+		window:       confDuration(cnf, "window", 5*time.Second),
+		maxLogs:      maxLogs,
+		msgThrottle:  confDuration(cnf, "message_throttle", time.Minute),
+		loggerLimit:  confInt(cnf, "logger_limit", 30),
+		loggerPeriod: confDuration(cnf, "logger_period", time.Minute),
+		sender:       newHTTPSender(cnf, false),
+		messages:     map[string]time.Time{},
+		loggers:      map[string][]time.Time{},
+	}
+}
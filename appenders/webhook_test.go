@@ -0,0 +1,195 @@
+package appenders
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// HTTP server which remembers bodies of received requests
+type webhookServer struct {
+	mu     sync.Mutex
+	bodies []string
+
+	// status codes returned for requests, after them 200 is returned
+	statuses []int
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+}
+
+func (s *webhookServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.bodies...)
+}
+
+func TestWebhookId(t *testing.T) {
+	appender := Webhook(golog.Conf{})
+	assert.Equal(t, "github.com/ivpusic/golog/appenders/webhook", appender.Id())
+}
+
+func TestWebhookGrouping(t *testing.T) {
+	ws := &webhookServer{}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	appender := Webhook(golog.Conf{
+		"url":    srv.URL,
+		"window": "50ms",
+	})
+	defer appender.Close()
+
+	appender.Append(golog.Log{Message: "not posted", Level: golog.INFO})
+	appender.Append(golog.Log{Message: "first", Level: golog.WARN, Logger: &golog.Logger{Name: "somelogger"}})
+	appender.Append(golog.Log{Message: "second \"quoted\"", Level: golog.ERROR})
+
+	// burst is posted after window passes
+	assert.Len(t, ws.received(), 0)
+	time.Sleep(300 * time.Millisecond)
+
+	bodies := ws.received()
+	assert.Len(t, bodies, 1)
+	assert.Equal(t, `{"text": "WARN somelogger: first\nERROR: second \"quoted\""}`, bodies[0])
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	ws := &webhookServer{}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	appender := Webhook(golog.Conf{
+		"url":      srv.URL,
+		"template": `{"items": [{{range $i, $log := .Logs}}{{if $i}}, {{end}}{"logger": {{json (logger $log)}}, "message": {{json $log.Message}}, "user": {{json (index $log.Ctx "user")}}}{{end}}]}`,
+		"window":   "1h",
+	})
+
+	ctx := golog.Ctx{"user": "john"}
+	appender.Append(golog.Log{
+		Message: "first",
+		Level:   golog.WARN,
+		Logger:  &golog.Logger{Name: "somelogger  "},
+		Ctx:     ctx,
+	})
+
+	// context is copied, so logger can change it
+	ctx["user"] = "jane"
+	assert.Nil(t, appender.Close())
+
+	bodies := ws.received()
+	assert.Len(t, bodies, 1)
+	assert.Equal(t, `{"items": [{"logger": "somelogger", "message": "first", "user": "john"}]}`, bodies[0])
+}
+
+func TestWebhookInvalidTemplate(t *testing.T) {
+	appender := Webhook(golog.Conf{"template": "{{.Missing"})
+
+	payload, err := appender.Payload(WebhookMessage{Logs: []golog.Log{{Message: "first", Level: golog.WARN}}})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"text": "WARN: first"}`, string(payload))
+}
+
+func TestWebhookMessageThrottle(t *testing.T) {
+	ws := &webhookServer{}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	appender := Webhook(golog.Conf{
+		"url":    srv.URL,
+		"window": "1h",
+	})
+
+	for i := 0; i < 3; i++ {
+		appender.Append(golog.Log{Message: "repeated", Level: golog.ERROR})
+	}
+
+	assert.Nil(t, appender.Flush())
+
+	// logs suppressed after post are reported with next post
+	appender.Append(golog.Log{Message: "repeated", Level: golog.ERROR})
+	appender.Append(golog.Log{Message: "other", Level: golog.ERROR})
+	assert.Nil(t, appender.Close())
+
+	bodies := ws.received()
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, `{"text": "ERROR: repeated\n2 logs are suppressed by throttling"}`, bodies[0])
+	assert.Equal(t, `{"text": "ERROR: other\n1 logs are suppressed by throttling"}`, bodies[1])
+}
+
+func TestWebhookLoggerLimit(t *testing.T) {
+	appender := Webhook(golog.Conf{
+		"message_throttle": "0",
+		"logger_limit":     "2",
+		"logger_period":    "1m",
+		"max_logs":         "3",
+	})
+
+	first := &golog.Logger{Name: "first"}
+	second := &golog.Logger{Name: "second"}
+	now := time.Now()
+
+	assert.False(t, appender.throttled(golog.Log{Message: "a", Logger: first}, now))
+	assert.False(t, appender.throttled(golog.Log{Message: "a", Logger: first}, now))
+	assert.True(t, appender.throttled(golog.Log{Message: "b", Logger: first}, now))
+	assert.False(t, appender.throttled(golog.Log{Message: "b", Logger: second}, now))
+
+	// limit is reset after logger period
+	assert.False(t, appender.throttled(golog.Log{Message: "c", Logger: first}, now.Add(time.Minute)))
+
+	appender.cleanup(now.Add(2 * time.Minute))
+	assert.Len(t, appender.loggers, 0)
+}
+
+func TestWebhookMaxLogs(t *testing.T) {
+	appender := Webhook(golog.Conf{
+		"message_throttle": "0",
+		"max_logs":         "1",
+		"window":           "1h",
+	})
+
+	appender.Append(golog.Log{Message: "first", Level: golog.WARN})
+	appender.Append(golog.Log{Message: "second", Level: golog.WARN})
+
+	msg := WebhookMessage{Logs: appender.logs, Omitted: appender.omitted}
+	assert.Equal(t, "WARN: first\n1 more logs are not included", msg.Text())
+}
+
+func TestWebhookRetry(t *testing.T) {
+	ws := &webhookServer{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	appender := Webhook(golog.Conf{
+		"url":     srv.URL,
+		"backoff": "1ms",
+	})
+
+	appender.Append(golog.Log{Message: "first", Level: golog.WARN})
+	assert.Nil(t, appender.Close())
+	assert.Len(t, ws.received(), 1)
+
+	// request is not retried on client errors
+	ws.statuses = []int{http.StatusBadRequest}
+	appender = Webhook(golog.Conf{"url": srv.URL})
+	appender.Append(golog.Log{Message: "first", Level: golog.WARN})
+	assert.NotNil(t, appender.Close())
+}
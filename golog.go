@@ -10,7 +10,15 @@ var (
 )
 
 func init() {
+	ResetLoggers()
+}
+
+// Will remove all existing loggers and make new default logger.
+// Loggers taken before reset keep working, but GetLogger will return new instances.
+// This is mostly useful for isolating tests.
+func ResetLoggers() {
 	loggers = map[string]*Logger{}
+	curnamelen = defaultnamelen
 	Default = GetLogger("default")
 }
 
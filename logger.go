@@ -45,8 +45,9 @@ var (
 
 	// limit when logger name will be normalized
 	// normalized names are shown in console using stdout appender
-	maxnamelen = 20
-	curnamelen = 7
+	maxnamelen     = 20
+	defaultnamelen = 7
+	curnamelen     = defaultnamelen
 
 	// supported name separators
 	separators []byte = []byte{'/', '.', '-'}
@@ -134,7 +135,12 @@ func (l *Logger) shouldAppend(lvl Level) bool {
 
 // Making and sending log entry to appenders if log level is appropriate.
 func (l *Logger) makeLog(msg interface{}, lvl Level, data []interface{}) {
-	log := Log{
+	l.appendLog(l.newLog(msg, lvl, data))
+}
+
+// Making log entry bound to this logger without sending it anywhere.
+func (l *Logger) newLog(msg interface{}, lvl Level, data []interface{}) Log {
+	return Log{
 		Time:    time.Now().UTC(),
 		Message: l.toString(msg),
 		Level:   lvl,
@@ -143,12 +149,30 @@ func (l *Logger) makeLog(msg interface{}, lvl Level, data []interface{}) {
 		Pid:     os.Getpid(),
 		Ctx:     l.ctx,
 	}
+}
 
+// Sending already made log entry to all appenders of this logger.
+func (l *Logger) appendLog(log Log) {
 	for _, appender := range l.appenders {
 		appender.Append(log)
 	}
 }
 
+// Returns new context which contains logger context extended with provided fields.
+// Logger context itself is not modified.
+func (l *Logger) extendContext(fields Ctx) Ctx {
+	ctx := make(Ctx, len(l.ctx)+len(fields))
+	for k, v := range l.ctx {
+		ctx[k] = v
+	}
+
+	for k, v := range fields {
+		ctx[k] = v
+	}
+
+	return ctx
+}
+
 func (l *Logger) toString(object interface{}) string {
 	return fmt.Sprintf("%v", object)
 }
@@ -11,9 +11,11 @@ type testAppender struct {
 	errorCount  int
 	msg         string
 	receivedCtx Ctx
+	logs        []Log
 }
 
 func (s *testAppender) Append(log Log) {
+	s.logs = append(s.logs, log)
 	s.msg = log.Message
 	s.count += 1
 	s.receivedCtx = log.Ctx
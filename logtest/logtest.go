@@ -0,0 +1,208 @@
+// Package logtest provides helpers for testing code which is using golog.
+//
+// Recorder is appender which keeps received logs in memory,
+// so tests can query them and make assertions about them.
+package logtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ivpusic/golog"
+)
+
+var recorders uint64
+
+// Representing appender which records all received logs.
+// It is safe to use recorder from multiple goroutines.
+type Recorder struct {
+	mu   sync.Mutex
+	id   string
+	logs []golog.Log
+}
+
+// Function for creating new recorder.
+// Every recorder has unique Id, so multiple recorders can be enabled
+// and disabled on the same logger independently.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		id: fmt.Sprintf("github.com/ivpusic/golog/logtest/%d", atomic.AddUint64(&recorders, 1)),
+	}
+}
+
+// Will enable new recorder on provided logger.
+// Recorder is disabled when test and all its subtests complete.
+func Record(t testing.TB, logger *golog.Logger) *Recorder {
+	r := NewRecorder()
+	logger.Enable(r)
+
+	t.Cleanup(func() {
+		logger.Disable(r)
+	})
+
+	return r
+}
+
+// Will remove all existing loggers, and register the same cleanup when test completes.
+// Use it when tested code is changing state of loggers taken with golog.GetLogger.
+func Isolate(t testing.TB) {
+	golog.ResetLoggers()
+	t.Cleanup(golog.ResetLoggers)
+}
+
+// Recording log.
+func (r *Recorder) Append(log golog.Log) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logs = append(r.logs, log)
+}
+
+// Getting Id of recorder.
+func (r *Recorder) Id() string {
+	return r.id
+}
+
+// Will return copy of all recorded logs.
+func (r *Recorder) Logs() []golog.Log {
+	return r.Filter(func(golog.Log) bool {
+		return true
+	})
+}
+
+// Will return messages of all recorded logs.
+func (r *Recorder) Messages() []string {
+	logs := r.Logs()
+
+	msgs := make([]string, len(logs))
+	for i, log := range logs {
+		msgs[i] = log.Message
+	}
+
+	return msgs
+}
+
+// Will return number of recorded logs.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.logs)
+}
+
+// Will remove all recorded logs.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logs = nil
+}
+
+// Will return recorded logs for which provided function returns true.
+func (r *Recorder) Filter(fn func(golog.Log) bool) []golog.Log {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	logs := []golog.Log{}
+	for _, log := range r.logs {
+		if fn(log) {
+			logs = append(logs, log)
+		}
+	}
+
+	return logs
+}
+
+// Will return recorded logs with provided level.
+func (r *Recorder) ByLevel(lvl golog.Level) []golog.Log {
+	return r.Filter(func(log golog.Log) bool {
+		return log.Level.Value == lvl.Value
+	})
+}
+
+// Will return recorded logs which message contains provided string.
+func (r *Recorder) Containing(substr string) []golog.Log {
+	return r.Filter(func(log golog.Log) bool {
+		return strings.Contains(log.Message, substr)
+	})
+}
+
+// Will return recorded logs which context contains provided key.
+func (r *Recorder) WithCtxKey(key string) []golog.Log {
+	return r.Filter(func(log golog.Log) bool {
+		_, ok := log.Ctx[key]
+		return ok
+	})
+}
+
+// Will return recorded logs which context contains provided key and value.
+func (r *Recorder) WithCtx(key string, value interface{}) []golog.Log {
+	return r.Filter(func(log golog.Log) bool {
+		v, ok := log.Ctx[key]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+func (r *Recorder) matching(lvl golog.Level, substr string) []golog.Log {
+	return r.Filter(func(log golog.Log) bool {
+		return log.Level.Value == lvl.Value && strings.Contains(log.Message, substr)
+	})
+}
+
+// Test will fail if there is no recorded log with provided level
+// which message contains provided string. First matching log is returned.
+func (r *Recorder) AssertLogged(t testing.TB, lvl golog.Level, substr string) golog.Log {
+	t.Helper()
+
+	logs := r.matching(lvl, substr)
+	if len(logs) == 0 {
+		t.Errorf("expected %s log containing %q, got logs:\n%s", lvl.Name, substr, r.dump())
+		return golog.Log{}
+	}
+
+	return logs[0]
+}
+
+// Test will fail if there is recorded log with provided level
+// which message contains provided string.
+func (r *Recorder) AssertNotLogged(t testing.TB, lvl golog.Level, substr string) {
+	t.Helper()
+
+	if len(r.matching(lvl, substr)) > 0 {
+		t.Errorf("unexpected %s log containing %q, got logs:\n%s", lvl.Name, substr, r.dump())
+	}
+}
+
+// Test will fail if number of recorded logs is different than provided number.
+func (r *Recorder) AssertCount(t testing.TB, count int) {
+	t.Helper()
+
+	if n := r.Len(); n != count {
+		t.Errorf("expected %d logs, got %d logs:\n%s", count, n, r.dump())
+	}
+}
+
+// Test will fail if there are recorded logs.
+func (r *Recorder) AssertEmpty(t testing.TB) {
+	t.Helper()
+	r.AssertCount(t, 0)
+}
+
+// Will return recorded logs in readable form, used in assertion messages.
+func (r *Recorder) dump() string {
+	logs := r.Logs()
+	if len(logs) == 0 {
+		return "\t(no logs)"
+	}
+
+	lines := make([]string, len(logs))
+	for i, log := range logs {
+		lines[i] = fmt.Sprintf("\t[%s] %s %v", log.Level.Name, log.Message, log.Ctx)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,130 @@
+package logtest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ivpusic/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testing.TB which only remembers reported errors
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecord(t *testing.T) {
+	Isolate(t)
+
+	logger := golog.GetLogger("logtest")
+	logger.Disable(golog.StdoutAppender())
+
+	var r *Recorder
+	t.Run("recording", func(t *testing.T) {
+		r = Record(t, logger)
+		logger.Info("some message")
+		r.AssertCount(t, 1)
+	})
+
+	// recorder is disabled after test completes
+	logger.Info("some message")
+	assert.Exactly(t, 1, r.Len())
+}
+
+func TestRecorderId(t *testing.T) {
+	first := NewRecorder()
+	second := NewRecorder()
+	assert.NotEqual(t, first.Id(), second.Id())
+
+	logger := golog.GetLogger("logtest").Copy()
+	logger.Enable(first)
+	logger.Enable(second)
+	logger.Disable(first)
+
+	logger.Info("some message")
+	assert.Exactly(t, 0, first.Len())
+	assert.Exactly(t, 1, second.Len())
+}
+
+func TestRecorderQueries(t *testing.T) {
+	Isolate(t)
+
+	logger := golog.GetLogger("logtest")
+	logger.Disable(golog.StdoutAppender())
+	r := Record(t, logger)
+
+	logger.Info("first message")
+	logger.Warn("second message")
+	logger.Copy().SetContext(golog.Ctx{"user": "someone"}).Error("third message")
+
+	assert.Equal(t, []string{"first message", "second message", "third message"}, r.Messages())
+	assert.Len(t, r.ByLevel(golog.WARN), 1)
+	assert.Len(t, r.Containing("message"), 3)
+	assert.Len(t, r.Containing("third"), 1)
+	assert.Len(t, r.WithCtxKey("user"), 1)
+	assert.Len(t, r.WithCtx("user", "someone"), 1)
+	assert.Len(t, r.WithCtx("user", "else"), 0)
+
+	r.Reset()
+	r.AssertEmpty(t)
+}
+
+func TestRecorderAssertions(t *testing.T) {
+	r := NewRecorder()
+	logger := golog.GetLogger("logtest").Copy()
+	logger.Enable(r)
+	logger.Error("something failed")
+
+	ft := &fakeT{}
+	log := r.AssertLogged(ft, golog.ERROR, "failed")
+	assert.Equal(t, "something failed", log.Message)
+	r.AssertNotLogged(ft, golog.INFO, "failed")
+	r.AssertCount(ft, 1)
+	assert.Len(t, ft.errors, 0)
+
+	r.AssertLogged(ft, golog.INFO, "failed")
+	r.AssertNotLogged(ft, golog.ERROR, "failed")
+	r.AssertCount(ft, 2)
+	assert.Len(t, ft.errors, 3)
+	assert.Contains(t, ft.errors[0], "[ERROR] something failed")
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	r := NewRecorder()
+	logger := golog.GetLogger("logtest").Copy()
+	logger.Enable(r)
+	logger.Disable(golog.StdoutAppender())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("some message")
+			}
+		}()
+	}
+
+	wg.Wait()
+	r.AssertCount(t, 1000)
+}
+
+func TestIsolate(t *testing.T) {
+	logger := golog.GetLogger("logtest")
+
+	t.Run("isolated", func(t *testing.T) {
+		Isolate(t)
+		assert.True(t, logger != golog.GetLogger("logtest"))
+	})
+
+	assert.True(t, logger != golog.GetLogger("logtest"))
+}
@@ -0,0 +1,260 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Representing slog handler which sends records to golog logger.
+// Attributes are converted to log context, and groups are
+// represented as nested contexts.
+type SlogHandler struct {
+	logger *Logger
+
+	// attributes collected using WithAttrs
+	ctx Ctx
+
+	// groups opened using WithGroup
+	groups []string
+}
+
+// Function for creating slog handler which sends records to provided logger.
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{
+		logger: logger,
+		ctx:    Ctx{},
+	}
+}
+
+// Reports whether provided slog level should be handled.
+// Decision is made by level and state of golog logger.
+func (h *SlogHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.logger.shouldAppend(LevelFromSlog(lvl))
+}
+
+// Sending slog record to golog logger.
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := copyCtx(h.ctx)
+
+	if r.NumAttrs() > 0 {
+		target := groupCtx(fields, h.groups)
+		r.Attrs(func(attr slog.Attr) bool {
+			addSlogAttr(target, attr)
+			return true
+		})
+	}
+
+	entry := h.logger.newLog(r.Message, LevelFromSlog(r.Level), nil)
+	if !r.Time.IsZero() {
+		entry.Time = r.Time.UTC()
+	}
+
+	entry.Ctx = h.logger.extendContext(fields)
+	h.logger.appendLog(entry)
+	return nil
+}
+
+// Will return new handler which adds provided attributes to every record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	handler := &SlogHandler{
+		logger: h.logger,
+		ctx:    copyCtx(h.ctx),
+		groups: h.groups,
+	}
+
+	target := groupCtx(handler.ctx, h.groups)
+	for _, attr := range attrs {
+		addSlogAttr(target, attr)
+	}
+
+	return handler
+}
+
+// Will return new handler which puts all further attributes into group with provided name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+
+	return &SlogHandler{
+		logger: h.logger,
+		ctx:    h.ctx,
+		groups: append(groups, name),
+	}
+}
+
+// Will make deep copy of context, including nested contexts made from groups.
+func copyCtx(ctx Ctx) Ctx {
+	copied := make(Ctx, len(ctx))
+	for k, v := range ctx {
+		if nested, ok := v.(Ctx); ok {
+			v = copyCtx(nested)
+		}
+
+		copied[k] = v
+	}
+
+	return copied
+}
+
+// Will return nested context which represents provided groups.
+// Missing nested contexts are created.
+func groupCtx(ctx Ctx, groups []string) Ctx {
+	for _, group := range groups {
+		nested, ok := ctx[group].(Ctx)
+		if !ok {
+			nested = Ctx{}
+			ctx[group] = nested
+		}
+
+		ctx = nested
+	}
+
+	return ctx
+}
+
+func addSlogAttr(ctx Ctx, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	// empty attributes should be ignored
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		ctx[attr.Key] = attr.Value.Any()
+		return
+	}
+
+	attrs := attr.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+
+	// attributes of group without name are inlined
+	if len(attr.Key) > 0 {
+		ctx = groupCtx(ctx, []string{attr.Key})
+	}
+
+	for _, nested := range attrs {
+		addSlogAttr(ctx, nested)
+	}
+}
+
+// Converting slog level to golog level.
+// Levels between slog levels are mapped to nearest lower golog level,
+// and levels above slog ERROR level are mapped to PANIC.
+func LevelFromSlog(lvl slog.Level) Level {
+	switch {
+	case lvl < slog.LevelInfo:
+		return DEBUG
+	case lvl < slog.LevelWarn:
+		return INFO
+	case lvl < slog.LevelError:
+		return WARN
+	case lvl < slog.LevelError+4:
+		return ERROR
+	default:
+		return PANIC
+	}
+}
+
+// Converting golog level to slog level.
+func LevelToSlog(lvl Level) slog.Level {
+	switch {
+	case lvl.Value < INFO.Value:
+		return slog.LevelDebug
+	case lvl.Value < WARN.Value:
+		return slog.LevelInfo
+	case lvl.Value < ERROR.Value:
+		return slog.LevelWarn
+	case lvl.Value < PANIC.Value:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+// Representing appender which forwards logs to slog handler.
+// Logger name and process id are sent as attributes,
+// log context is sent as attributes and attached data is sent under data key.
+type Slog struct {
+	handler slog.Handler
+}
+
+// Appending logs to slog handler.
+func (s *Slog) Append(log Log) {
+	ctx := context.Background()
+
+	lvl := LevelToSlog(log.Level)
+	if !s.handler.Enabled(ctx, lvl) {
+		return
+	}
+
+	r := slog.NewRecord(log.Time, lvl, log.Message, 0)
+	if log.Logger != nil {
+		r.AddAttrs(slog.String("logger", strings.TrimSpace(log.Logger.Name)))
+	}
+
+	r.AddAttrs(slog.Int("pid", log.Pid))
+	r.AddAttrs(slogAttrs(log.Ctx)...)
+
+	if len(log.Data) > 0 {
+		r.AddAttrs(slog.Any("data", log.Data))
+	}
+
+	if err := s.handler.Handle(ctx, r); err != nil {
+		fmt.Println(err.Error())
+		if log.Logger != nil && log.Logger.DoPanic {
+			panic(err)
+		}
+	}
+}
+
+// Getting Id of slog appender
+// Id of slog appender is "github.com/ivpusic/golog/slog"
+func (s *Slog) Id() string {
+	return "github.com/ivpusic/golog/slog"
+}
+
+// Function for creating slog appender which forwards logs to provided handler.
+// Don't use handler which is sending records back to the same logger.
+func SlogAppender(handler slog.Handler) *Slog {
+	return &Slog{
+		handler: handler,
+	}
+}
+
+// Converting context to slog attributes. Nested contexts are converted to groups.
+func slogAttrs(ctx map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(ctx))
+	for k := range ctx {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		switch v := ctx[k].(type) {
+		case Ctx:
+			attrs = append(attrs, slog.Attr{Key: k, Value: slog.GroupValue(slogAttrs(v)...)})
+		case map[string]interface{}:
+			attrs = append(attrs, slog.Attr{Key: k, Value: slog.GroupValue(slogAttrs(v)...)})
+		default:
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+
+	return attrs
+}
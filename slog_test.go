@@ -0,0 +1,118 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("slog-logger")
+	logger.Enable(ta)
+
+	sl := slog.New(NewSlogHandler(logger))
+	sl.Info("some message", "key", "value", "num", 3)
+
+	assert.Exactly(t, 1, ta.count)
+	assert.Equal(t, "some message", ta.msg)
+	assert.Equal(t, "value", ta.receivedCtx["key"])
+	assert.Equal(t, int64(3), ta.receivedCtx["num"])
+
+	sl.Error("some error")
+	assert.Exactly(t, 1, ta.errorCount)
+}
+
+func TestSlogHandlerLevel(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("slog-logger")
+	logger.Enable(ta)
+	logger.Level = WARN
+
+	sl := slog.New(NewSlogHandler(logger))
+	sl.Debug("some message")
+	sl.Info("some message")
+	assert.Exactly(t, 0, ta.count)
+
+	sl.Warn("some message")
+	assert.Exactly(t, 1, ta.count)
+}
+
+func TestSlogHandlerGroups(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("slog-logger")
+	logger.Enable(ta)
+
+	sl := slog.New(NewSlogHandler(logger)).
+		With("app", "test").
+		WithGroup("request").
+		With("method", "GET")
+
+	sl.Info("some message", "path", "/", slog.Group("user", "id", 1))
+
+	assert.Equal(t, "test", ta.receivedCtx["app"])
+	assert.Equal(t, Ctx{
+		"method": "GET",
+		"path":   "/",
+		"user":   Ctx{"id": int64(1)},
+	}, ta.receivedCtx["request"])
+
+	// group without attributes should be omitted
+	sl = slog.New(NewSlogHandler(logger)).WithGroup("empty")
+	sl.Info("some message")
+	_, ok := ta.receivedCtx["empty"]
+	assert.False(t, ok)
+}
+
+func TestSlogLevels(t *testing.T) {
+	levels := []Level{DEBUG, INFO, WARN, ERROR, PANIC}
+	for _, lvl := range levels {
+		assert.Equal(t, lvl, LevelFromSlog(LevelToSlog(lvl)))
+	}
+
+	assert.Equal(t, INFO, LevelFromSlog(slog.LevelInfo+2))
+}
+
+func TestSlogAppender(t *testing.T) {
+	defer cleanupTest()
+
+	buf := &bytes.Buffer{}
+	appender := SlogAppender(slog.NewJSONHandler(buf, nil))
+	assert.Equal(t, "github.com/ivpusic/golog/slog", appender.Id())
+
+	logger := GetLogger("slog-logger")
+	logger.Enable(appender)
+	logger.Copy().SetContext(Ctx{
+		"key":    "value",
+		"nested": Ctx{"num": 1},
+	}).Warn("some message", "data")
+
+	entry := map[string]interface{}{}
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "some message", entry["msg"])
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "slog-logger", entry["logger"])
+	assert.Equal(t, "value", entry["key"])
+	assert.Equal(t, map[string]interface{}{"num": float64(1)}, entry["nested"])
+	assert.Equal(t, []interface{}{"data"}, entry["data"])
+
+	_, err = time.Parse(time.RFC3339Nano, entry["time"].(string))
+	assert.Nil(t, err)
+
+	// debug records are disabled in json handler by default
+	buf.Reset()
+	logger.Debug("some message")
+	assert.Exactly(t, 0, buf.Len())
+}
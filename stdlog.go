@@ -0,0 +1,129 @@
+package golog
+
+import (
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Writer which receives output of standard library logger,
+// and converts every written line into golog entry.
+type stdWriter struct {
+	logger *Logger
+	level  Level
+
+	// standard library logger which is writing to this writer
+	// its prefix and flags are used for parsing written lines
+	std *log.Logger
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	if !w.logger.shouldAppend(w.level) {
+		return len(p), nil
+	}
+
+	text := strings.TrimSuffix(string(p), "\n")
+	text, fields := parseStdHeader(text, w.std.Prefix(), w.std.Flags())
+
+	for i, line := range strings.Split(text, "\n") {
+		// first line is always sent, even if it is empty,
+		// because it represents one call of standard library logger
+		if i > 0 && len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		entry := w.logger.newLog(line, w.level, nil)
+		entry.Ctx = w.logger.extendContext(fields)
+		w.logger.appendLog(entry)
+	}
+
+	return len(p), nil
+}
+
+// Removes header written by standard library logger from line.
+// Prefix, file and line number found in header are returned as fields.
+// Date and time are dropped, because every golog entry has its own time.
+func parseStdHeader(line, prefix string, flags int) (string, Ctx) {
+	fields := Ctx{}
+
+	stripPrefix := func() {
+		if len(prefix) > 0 && strings.HasPrefix(line, prefix) {
+			line = line[len(prefix):]
+			fields["prefix"] = strings.TrimSpace(prefix)
+		}
+	}
+
+	if flags&log.Lmsgprefix == 0 {
+		stripPrefix()
+	}
+
+	// 2009/01/23
+	if flags&log.Ldate != 0 && len(line) >= 11 {
+		line = line[11:]
+	}
+
+	// 01:23:23 or 01:23:23.123123
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		length := 9
+		if flags&log.Lmicroseconds != 0 {
+			length = 16
+		}
+
+		if len(line) >= length {
+			line = line[length:]
+		}
+	}
+
+	// /a/b/c/d.go:23 or d.go:23
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if end := strings.Index(line, ": "); end > 0 {
+			location := line[:end]
+			if sep := strings.LastIndexByte(location, ':'); sep > 0 {
+				fields["file"] = location[:sep]
+				if num, err := strconv.Atoi(location[sep+1:]); err == nil {
+					fields["line"] = num
+				}
+			}
+
+			line = line[end+2:]
+		}
+	}
+
+	if flags&log.Lmsgprefix != 0 {
+		stripPrefix()
+	}
+
+	return line, fields
+}
+
+// Will create standard library logger which sends everything
+// written to it to provided golog logger, using provided level.
+// Prefix and flags of returned logger can be changed as usual,
+// and they will be extracted to log context.
+func NewStdLogger(logger *Logger, lvl Level) *log.Logger {
+	w := &stdWriter{
+		logger: logger,
+		level:  lvl,
+	}
+
+	w.std = log.New(w, "", 0)
+	return w.std
+}
+
+// Will redirect output of standard library log package to provided golog logger,
+// using provided level. Prefix and flags of standard logger are kept and parsed.
+// Returned function restores previous output of standard library logger.
+func RedirectStdLog(logger *Logger, lvl Level) func() {
+	std := log.Default()
+	old := std.Writer()
+
+	std.SetOutput(&stdWriter{
+		logger: logger,
+		level:  lvl,
+		std:    std,
+	})
+
+	return func() {
+		std.SetOutput(old)
+	}
+}
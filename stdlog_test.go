@@ -0,0 +1,107 @@
+package golog
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("std-logger")
+	logger.Enable(ta)
+
+	std := NewStdLogger(logger, WARN)
+	std.Printf("some %s", "message")
+
+	assert.Exactly(t, 1, ta.count)
+	assert.Exactly(t, 1, ta.errorCount)
+	assert.Equal(t, "some message", ta.msg)
+}
+
+func TestNewStdLoggerLevel(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("std-logger")
+	logger.Enable(ta)
+	logger.Level = ERROR
+
+	std := NewStdLogger(logger, INFO)
+	std.Print("some message")
+	assert.Exactly(t, 0, ta.count)
+}
+
+func TestNewStdLoggerMultiline(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("std-logger")
+	logger.Enable(ta)
+
+	std := NewStdLogger(logger, INFO)
+	std.Print("first\nsecond\n\nthird\n")
+
+	assert.Exactly(t, 3, ta.count)
+	assert.Equal(t, "third", ta.msg)
+}
+
+func TestNewStdLoggerHeader(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("std-logger")
+	logger.AddContextKey("key", "value")
+	logger.Enable(ta)
+
+	std := NewStdLogger(logger, INFO)
+	std.SetPrefix("[lib] ")
+	std.SetFlags(log.LstdFlags | log.Lmicroseconds | log.Lshortfile)
+	std.Print("some message")
+
+	assert.Equal(t, "some message", ta.msg)
+	assert.Equal(t, "[lib]", ta.receivedCtx["prefix"])
+	assert.Equal(t, "stdlog_test.go", ta.receivedCtx["file"])
+	assert.IsType(t, 0, ta.receivedCtx["line"])
+	assert.Equal(t, "value", ta.receivedCtx["key"])
+
+	// logger context should not be changed
+	_, ok := logger.ctx["file"]
+	assert.False(t, ok)
+}
+
+func TestParseStdHeaderMsgPrefix(t *testing.T) {
+	msg, fields := parseStdHeader("2009/01/23 01:23:23 d.go:23: [lib] some: message", "[lib] ",
+		log.LstdFlags|log.Lshortfile|log.Lmsgprefix)
+
+	assert.Equal(t, "some: message", msg)
+	assert.Equal(t, Ctx{"prefix": "[lib]", "file": "d.go", "line": 23}, fields)
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("std-logger")
+	logger.Enable(ta)
+
+	buf := &bytes.Buffer{}
+	out := log.Writer()
+	log.SetOutput(buf)
+	defer log.SetOutput(out)
+
+	restore := RedirectStdLog(logger, INFO)
+	log.Print("redirected message")
+	assert.Exactly(t, 1, ta.count)
+	assert.Equal(t, "redirected message", ta.msg)
+	assert.Exactly(t, 0, buf.Len())
+
+	restore()
+	log.Print("not redirected message")
+	assert.Exactly(t, 1, ta.count)
+	assert.True(t, buf.Len() > 0)
+}
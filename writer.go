@@ -0,0 +1,208 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	// lines longer than this limit are split into multiple logs
+	maxWriterLineLen = 64 * 1024
+
+	// level names which can be detected at the beginning of line
+	writerLevels = map[string]Level{
+		"TRACE":    DEBUG,
+		"DEBUG":    DEBUG,
+		"INFO":     INFO,
+		"WARN":     WARN,
+		"WARNING":  WARN,
+		"ERR":      ERROR,
+		"ERROR":    ERROR,
+		"CRIT":     PANIC,
+		"CRITICAL": PANIC,
+		"FATAL":    PANIC,
+		"PANIC":    PANIC,
+	}
+
+	errWriterClosed = errors.New("golog: writer is closed")
+)
+
+// Writer which splits written bytes into lines,
+// and makes one log for every line.
+type logWriter struct {
+	mu     sync.Mutex
+	logger *Logger
+	level  Level
+	closed bool
+
+	// should level be detected from line prefix
+	detect bool
+
+	// part of line which is not yet terminated with new line
+	buf []byte
+
+	// level of line which is already partially sent because it was too long
+	partial *Level
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errWriterClosed
+	}
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			w.flushLong()
+			break
+		}
+
+		w.buf = append(w.buf, p[:i]...)
+		w.flushLong()
+		w.emit(w.buf, true)
+		w.buf = w.buf[:0]
+		p = p[i+1:]
+	}
+
+	return n, nil
+}
+
+// Will send remaining part of unterminated line, and reject all further writes.
+func (w *logWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	if len(w.buf) > 0 {
+		w.emit(w.buf, true)
+		w.buf = nil
+	}
+
+	w.closed = true
+	return nil
+}
+
+// If buffered line is too long, send it in parts.
+// Line is never split in the middle of utf8 character.
+func (w *logWriter) flushLong() {
+	for len(w.buf) > maxWriterLineLen {
+		end := maxWriterLineLen
+		for end > 0 && !utf8.RuneStart(w.buf[end]) {
+			end--
+		}
+
+		if end == 0 {
+			end = maxWriterLineLen
+		}
+
+		w.emit(w.buf[:end], false)
+		w.buf = append(w.buf[:0], w.buf[end:]...)
+	}
+}
+
+// Making log from line. If line is not complete, level of its first part
+// is remembered, and used for rest of the line.
+func (w *logWriter) emit(line []byte, complete bool) {
+	text := strings.TrimSuffix(string(line), "\r")
+	lvl := w.level
+
+	// nothing is left of line which is already sent in parts
+	if complete && w.partial != nil && len(text) == 0 {
+		w.partial = nil
+		return
+	}
+
+	if w.partial != nil {
+		lvl = *w.partial
+	} else if w.detect {
+		lvl, text = detectLevel(text, lvl)
+	}
+
+	if complete {
+		w.partial = nil
+	} else {
+		w.partial = &lvl
+	}
+
+	if w.logger.shouldAppend(lvl) {
+		w.logger.makeLog(text, lvl, nil)
+	}
+}
+
+// Will try to find level name at the beginning of line.
+// Supported forms are for example `ERROR message`, `error: message` and `[ERROR] message`.
+// If level cannot be found, provided default level and unchanged line are returned.
+func detectLevel(line string, def Level) (Level, string) {
+	rest := strings.TrimLeft(line, " \t")
+
+	bracket := strings.HasPrefix(rest, "[")
+	if bracket {
+		rest = rest[1:]
+	}
+
+	end := 0
+	for end < len(rest) && isLetter(rest[end]) {
+		end++
+	}
+
+	lvl, ok := writerLevels[strings.ToUpper(rest[:end])]
+	if !ok {
+		return def, line
+	}
+
+	rest = rest[end:]
+	if bracket {
+		if !strings.HasPrefix(rest, "]") {
+			return def, line
+		}
+
+		rest = rest[1:]
+	}
+
+	rest = strings.TrimPrefix(rest, ":")
+	if len(rest) > 0 && rest[0] != ' ' && rest[0] != '\t' {
+		return def, line
+	}
+
+	return lvl, strings.TrimLeft(rest, " \t")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Will return writer which makes one log with provided level for every written line.
+// Writer can be used for example as output of exec.Cmd,
+// or as output of library which is writing to io.Writer.
+// Writer should be closed when you are done with it,
+// so last unterminated line is not lost.
+func (l *Logger) Writer(lvl Level) io.WriteCloser {
+	return &logWriter{
+		logger: l,
+		level:  lvl,
+	}
+}
+
+// Same as Writer, but level of every line is detected from level name
+// at the beginning of line, for example `ERROR message` or `[warn] message`.
+// Detected level name is removed from message.
+// Provided level is used for lines without level name.
+func (l *Logger) LevelWriter(lvl Level) io.WriteCloser {
+	return &logWriter{
+		logger: l,
+		level:  lvl,
+		detect: true,
+	}
+}
@@ -0,0 +1,122 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("writer-logger")
+	logger.Enable(ta)
+
+	w := logger.Writer(INFO)
+	fmt.Fprint(w, "first line\nsec")
+	assert.Exactly(t, 1, ta.count)
+	assert.Equal(t, "first line", ta.msg)
+
+	fmt.Fprint(w, "ond line\r\nthird")
+	assert.Exactly(t, 2, ta.count)
+	assert.Equal(t, "second line", ta.msg)
+	assert.Equal(t, INFO, ta.logs[1].Level)
+
+	// unterminated line is sent on close
+	assert.Nil(t, w.Close())
+	assert.Exactly(t, 3, ta.count)
+	assert.Equal(t, "third", ta.msg)
+
+	_, err := fmt.Fprint(w, "some message\n")
+	assert.NotNil(t, err)
+	assert.Exactly(t, 3, ta.count)
+}
+
+func TestWriterLevel(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("writer-logger")
+	logger.Enable(ta)
+	logger.Level = WARN
+
+	w := logger.Writer(INFO)
+	fmt.Fprint(w, "some message\n")
+	assert.Exactly(t, 0, ta.count)
+}
+
+func TestWriterLongLine(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("writer-logger")
+	logger.Enable(ta)
+
+	old := maxWriterLineLen
+	maxWriterLineLen = 10
+	defer func() {
+		maxWriterLineLen = old
+	}()
+
+	w := logger.LevelWriter(INFO)
+	fmt.Fprint(w, "ERROR 0123456789")
+	fmt.Fprint(w, "abcdefghij\n")
+	assert.Exactly(t, 3, ta.count)
+
+	var msgs []string
+	for _, log := range ta.logs {
+		assert.Equal(t, ERROR, log.Level)
+		msgs = append(msgs, log.Message)
+	}
+
+	assert.Equal(t, "0123456789abcdefghij", strings.Join(msgs, ""))
+
+	// multibyte characters should not be split
+	fmt.Fprint(w, "ééééééé\n")
+	assert.Equal(t, "éé", ta.msg)
+	assert.Equal(t, INFO, ta.logs[len(ta.logs)-1].Level)
+
+	// line split exactly at limit doesn't make empty log
+	count := ta.count
+	fmt.Fprint(w, "0123456789\r\n")
+	fmt.Fprint(w, "abcdefghij")
+	fmt.Fprint(w, "0")
+	fmt.Fprint(w, "\n")
+	assert.Exactly(t, count+3, ta.count)
+	assert.Equal(t, "0", ta.msg)
+	assert.Nil(t, w.Close())
+	assert.Exactly(t, count+3, ta.count)
+}
+
+func TestLevelWriter(t *testing.T) {
+	defer cleanupTest()
+	ta := &testAppender{}
+
+	logger := GetLogger("writer-logger")
+	logger.Enable(ta)
+
+	w := logger.LevelWriter(INFO)
+	fmt.Fprint(w, "ERROR some error\n[warn] some warning\ndebug: some debug\nsome info\n")
+	fmt.Fprint(w, "Errors are here\n[ERROR some message\n")
+
+	expected := []struct {
+		level Level
+		msg   string
+	}{
+		{ERROR, "some error"},
+		{WARN, "some warning"},
+		{DEBUG, "some debug"},
+		{INFO, "some info"},
+		{INFO, "Errors are here"},
+		{INFO, "[ERROR some message"},
+	}
+
+	assert.Equal(t, len(expected), len(ta.logs))
+	for i, e := range expected {
+		assert.Equal(t, e.level, ta.logs[i].Level)
+		assert.Equal(t, e.msg, ta.logs[i].Message)
+	}
+}